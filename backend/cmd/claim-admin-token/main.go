@@ -0,0 +1,66 @@
+// Command claim-admin-token issues an admin token for an event created before
+// events had one, so its organizer can change or finalize it again. Each
+// event can be claimed once; hand the printed token to the organizer.
+//
+//	claim-admin-token -db ./events.db <event-id>
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/config"
+	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "claim-admin-token:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string) error {
+	dsn := config.Default().Database
+	if v, ok := os.LookupEnv(config.EnvPrefix + "DB"); ok {
+		dsn = v
+	}
+
+	fs := flag.NewFlagSet("claim-admin-token", flag.ContinueOnError)
+	fs.StringVar(&dsn, "db", dsn, "SQLite database path, or a postgres:// URL (env "+config.EnvPrefix+"DB)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: claim-admin-token [-db dsn] <event-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	store, err := database.Open(dsn)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	// The token column arrives with a migration
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	token, err := store.ClaimAdminToken(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+)
+
+// runJanitor purges expired events on every tick until ctx is cancelled
+func runJanitor(ctx context.Context, store database.EventStore, policy database.RetentionPolicy, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeExpiredEvents(ctx, store, policy)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeExpiredEvents runs a single retention pass and logs the outcome
+func purgeExpiredEvents(ctx context.Context, store database.EventStore, policy database.RetentionPolicy) {
+	purged, err := store.PurgeExpiredEvents(ctx, policy, time.Now())
+	if err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "failed to purge expired events", "error", err)
+	}
+	if purged > 0 {
+		slog.InfoContext(ctx, "purged expired events", "count", purged)
+	}
+}
@@ -1,78 +1,176 @@
 package main
 
 import (
-	"database/sql"
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net"
 	"net/http"
-
-	_ "github.com/mattn/go-sqlite3"
-
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	_ "time/tzdata" // event time zones must resolve even without system zoneinfo
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/config"
+	"github.com/jleikdra/finn-en-dato/backend/internal/cors"
 	"github.com/jleikdra/finn-en-dato/backend/internal/database"
 	"github.com/jleikdra/finn-en-dato/backend/internal/handlers"
+	"github.com/jleikdra/finn-en-dato/backend/internal/logging"
+	"github.com/jleikdra/finn-en-dato/backend/internal/metrics"
+	"github.com/jleikdra/finn-en-dato/backend/internal/openapi"
+	"github.com/jleikdra/finn-en-dato/backend/internal/ratelimit"
+	"github.com/jleikdra/finn-en-dato/backend/internal/spa"
+	"github.com/jleikdra/finn-en-dato/frontend"
 )
 
 func main() {
+	// config
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+
+	// logging
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fatal("invalid logging configuration", err)
+	}
+	slog.SetDefault(logger)
+
+	// stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// db init
-	db := initDatabase()
+	db := initDatabase(ctx, cfg.Database)
+	store := database.Instrument(db)
+
+	// retention janitor
+	var janitor sync.WaitGroup
+	policy := database.RetentionPolicy{
+		AfterLastDate:  cfg.RetainAfterLastDate,
+		AfterFinalized: cfg.RetainAfterFinalized,
+	}
+	if policy.Enabled() {
+		janitor.Go(func() {
+			runJanitor(ctx, store, policy, cfg.JanitorInterval)
+		})
+	}
+
+	// abuse protection
+	proxies, err := ratelimit.ParseProxies(cfg.TrustedProxies)
+	if err != nil {
+		fatal("invalid trusted proxies", err)
+	}
+	limits := handlers.Limits{
+		PerClient:      ratelimit.New(cfg.RateLimit, cfg.RateBurst),
+		PerEvent:       ratelimit.New(cfg.EventRateLimit, cfg.EventRateBurst),
+		TrustedProxies: proxies,
+		MaxBodyBytes:   cfg.MaxBodyBytes,
+	}
 
 	// handler setup
-	eventHandler := handlers.NewEventHandler(db)
+	eventHandler := handlers.NewEventHandler(store, limits)
+	healthHandler := handlers.NewHealthHandler(db)
 
 	// routes
-	mux := setupRoutes(eventHandler)
-
-	// cors
-	handler := corsMiddleware(mux)
+	handler := setupRoutes(eventHandler, healthHandler, cfg)
 
 	// server start
-	log.Println("Server starting on :8080...")
-	log.Fatal(http.ListenAndServe(":8080", handler))
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		fatal("failed to listen", err)
+	}
+	slog.Info("server starting", "addr", ln.Addr().String())
+	err = serve(ctx, newServer(cfg.Listen, handler), ln, cfg.ShutdownTimeout)
+	if err != nil {
+		slog.Error("server stopped with an error", "error", err)
+	}
+
+	// Wait for the janitor to stop before the database goes away
+	stop()
+	janitor.Wait()
+
+	if err := db.Close(); err != nil {
+		slog.Error("failed to close database", "error", err)
+	}
+	slog.Info("server stopped")
+	if err != nil {
+		os.Exit(1)
+	}
 }
 
 // helper functions
-func initDatabase() *sql.DB {
-	db, err := sql.Open("sqlite3", "./events.db")
+func initDatabase(ctx context.Context, dsn string) *database.SQLStore {
+	store, err := database.Open(dsn)
 	if err != nil {
-		log.Fatal("Failed to open database:", err)
+		fatal("failed to open database", err)
 	}
 
-	err = database.CreateTables(db)
+	err = store.Migrate(ctx)
 	if err != nil {
-		log.Fatal("Failed to create tables:", err)
+		fatal("failed to migrate database", err)
 	}
 
-	log.Println("Database initialized successfully")
-	return db
+	slog.Info("database initialized", "schema_version", store.LatestSchemaVersion())
+	return store
 }
 
-func setupRoutes(handler *handlers.EventHandler) *http.ServeMux {
-	mux := http.NewServeMux()
-
-	// API routes
-	mux.HandleFunc("/api/events", handler.HandleEvents)
-	mux.HandleFunc("/api/events/", handler.HandleEventsByID)
+// frontendHandler serves the frontend from dir, or from the build embedded
+// in the binary when dir is empty. It returns nil if nothing was embedded.
+func frontendHandler(dir string) http.Handler {
+	if dir != "" {
+		slog.Info("serving frontend from disk", "dir", dir)
+		return spa.NewDev(os.DirFS(dir))
+	}
 
-	// Serve React frontend static files
-	fs := http.FileServer(http.Dir("../frontend/build/"))
-	mux.Handle("/", fs)
+	app, err := spa.New(frontend.Dist())
+	if err != nil {
+		slog.Warn("no frontend embedded, serving the API only", "error", err)
+		return nil
+	}
+	return app
+}
 
-	return mux
+// fatal logs an error that keeps the server from starting and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
 
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Allow requests from the React dev server (typically on port 3000)
-		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		
-
-		next.ServeHTTP(w, r)
+func setupRoutes(handler *handlers.EventHandler, health *handlers.HealthHandler, cfg *config.Config) http.Handler {
+	mux := http.NewServeMux()
+
+	// cors
+	c := cors.New(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedHeaders:   []string{"Content-Type", handlers.AdminTokenHeader, handlers.EditTokenHeader, requestIDHeader},
+		ExposedHeaders:   []string{requestIDHeader, "Retry-After", "Deprecation", "Sunset", "Link"},
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
 	})
+
+	// API routes, plus the unversioned paths older clients still use
+	handlers.Mount(mux, handler.Routes(), c.Handler)
+	handlers.Mount(mux, handlers.LegacyRoutes(handler.Routes()), c.Handler)
+	handlers.Mount(mux, []handlers.Route{
+		{Method: http.MethodGet, Pattern: "/api/openapi.json", Handler: openapi.Handler().ServeHTTP},
+	}, c.Handler)
+	mux.HandleFunc("/api/", handlers.NotFound)
+
+	// Probes and metrics are for infrastructure, not browsers, so they skip CORS
+	handlers.Mount(mux, health.Routes(), nil)
+	mux.Handle("/metrics", metrics.Handler())
+
+	// Serve the React frontend, with index.html for its client-side routes
+	if app := frontendHandler(cfg.StaticDir); app != nil {
+		mux.Handle("/", app)
+	}
+
+	return requestIDMiddleware(metricsMiddleware(accessLogMiddleware(mux)))
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/metrics"
+)
+
+// metricsMiddleware records request counts, latencies and in-flight requests
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		labels := []string{routeLabel(r), methodLabel(r.Method), strconv.Itoa(rec.status)}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+	})
+}
+
+// routeLabel names the route that served a request after the mux has matched
+// it, e.g. /api/events/{id}/results, so event IDs do not end up as label
+// values
+func routeLabel(r *http.Request) string {
+	// Drop the method from patterns such as "GET /api/events/{id}"
+	pattern := r.Pattern
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = pattern[i+1:]
+	}
+
+	switch pattern {
+	case "", "/api/":
+		return "other"
+	case "/":
+		return "static"
+	}
+	return pattern
+}
+
+// methodLabel keeps arbitrary client-chosen methods out of the label values
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "other"
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// readHeaderTimeout limits how long a client may take to send headers
+	readHeaderTimeout = 5 * time.Second
+
+	// readTimeout limits how long a client may take to send a whole request
+	readTimeout = 15 * time.Second
+
+	// writeTimeout limits how long writing a response may take
+	writeTimeout = 30 * time.Second
+
+	// idleTimeout closes keep-alive connections that sit unused
+	idleTimeout = 2 * time.Minute
+
+	// maxHeaderBytes caps the size of request headers
+	maxHeaderBytes = 64 << 10
+)
+
+// newServer creates the HTTP server with timeouts suitable for the internet
+func newServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+}
+
+// serve runs srv on ln until ctx is cancelled, then stops accepting
+// connections and waits up to drainTimeout for in-flight requests to finish
+func serve(ctx context.Context, srv *http.Server, ln net.Listener, drainTimeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, draining in-flight requests", "timeout", drainTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Whatever is still running gets cut off
+		srv.Close()
+		return fmt.Errorf("failed to drain requests: %w", err)
+	}
+
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
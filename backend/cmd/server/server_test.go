@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/config"
+	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+	"github.com/jleikdra/finn-en-dato/backend/internal/handlers"
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// gate holds up the first write to a table, inside its transaction, until
+// it is released
+type gate struct {
+	table   string
+	once    sync.Once
+	entered chan struct{}
+	release chan struct{}
+}
+
+// activeGate is the gate consulted by every sqlite3_gated connection
+var activeGate atomic.Pointer[gate]
+
+func init() {
+	sql.Register("sqlite3_gated", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			conn.RegisterUpdateHook(func(op int, db, table string, rowid int64) {
+				g := activeGate.Load()
+				if g == nil || table != g.table {
+					return
+				}
+				g.once.Do(func() {
+					close(g.entered)
+					<-g.release
+				})
+			})
+			return nil
+		},
+	})
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	responsesGate := &gate{table: "responses", entered: make(chan struct{}), release: make(chan struct{})}
+	activeGate.Store(responsesGate)
+	t.Cleanup(func() { activeGate.Store(nil) })
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3_gated", filepath.Join(t.TempDir(), "events.db")+"?_busy_timeout=5000&_txlock=immediate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// Let a held-up submission finish if the test fails before releasing it
+	release := sync.OnceFunc(func() { close(responsesGate.release) })
+	t.Cleanup(release)
+	store := database.NewSQLiteStore(db)
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := store.CreateEvent(ctx, models.CreateEventRequest{
+		Name:  "Julebord",
+		Dates: []models.CreateDateRequest{{Date: "2035-12-05", StartTime: "18:00", EndTime: "23:00"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := setupRoutes(handlers.NewEventHandler(store, handlers.Limits{}), handlers.NewHealthHandler(store), config.Default())
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(serveCtx, newServer(ln.Addr().String(), handler), ln, 10*time.Second)
+	}()
+
+	body, _ := json.Marshal(models.SubmitResponseRequest{
+		Name:      "Kari",
+		Responses: []models.ResponseRequest{{EventDateID: created.Dates[0].ID, Availability: models.AvailabilityYes}},
+	})
+	url := "http://" + ln.Addr().String() + "/api/v1/events/" + created.ID + "/respond"
+	type result struct {
+		status int
+		err    error
+	}
+	responded := make(chan result, 1)
+	go func() {
+		resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			responded <- result{err: err}
+			return
+		}
+		resp.Body.Close()
+		responded <- result{status: resp.StatusCode}
+	}()
+
+	// The answers are now being written, inside their transaction
+	select {
+	case <-responsesGate.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the submission never reached the database")
+	}
+	cancel()
+
+	// New connections are refused while the submission is held up
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", ln.Addr().String(), 100*time.Millisecond)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("the listener stayed open after shutdown began")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-served:
+		t.Fatalf("serve returned %v with a request in flight", err)
+	default:
+	}
+
+	release()
+	if err := <-served; err != nil {
+		t.Fatalf("serve = %v", err)
+	}
+
+	// serve has returned, so the submission has been answered and committed
+	res := <-responded
+	if res.err != nil || res.status != http.StatusCreated {
+		t.Fatalf("submission = %d, %v; want 201", res.status, res.err)
+	}
+	results, err := store.GetEventResults(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Respondents) != 1 || results.Summary[created.Dates[0].ID].AvailableCount != 1 {
+		t.Errorf("the drained submission was not committed: %+v", results)
+	}
+}
+
+func TestServeDrainTimeout(t *testing.T) {
+	started := make(chan struct{})
+	stuck := make(chan struct{})
+	defer close(stuck)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-stuck
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, newServer(ln.Addr().String(), handler), ln, 50*time.Millisecond)
+	}()
+
+	go func() {
+		if resp, err := http.Get("http://" + ln.Addr().String()); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+
+	select {
+	case err := <-served:
+		if err == nil {
+			t.Error("serve = nil for a request that outlived the drain timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not give up after the drain timeout")
+	}
+}
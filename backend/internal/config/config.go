@@ -0,0 +1,485 @@
+// Package config loads the server configuration.
+//
+// Every setting can come from a command-line flag, an environment variable
+// or a config file. When a setting is given more than once the first of
+// these wins:
+//
+//  1. command-line flags, e.g. -listen :9000
+//  2. environment variables, e.g. FINN_LISTEN=:9000
+//  3. the config file named by -config or FINN_CONFIG
+//  4. built-in defaults
+//
+// The config file holds one "name = value" pair per line using the flag
+// names; blank lines and lines starting with # are ignored.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/cors"
+	"github.com/jleikdra/finn-en-dato/backend/internal/ratelimit"
+)
+
+// EnvPrefix is prepended to a setting's upper-cased name to form its
+// environment variable, e.g. FINN_STATIC_DIR for static-dir
+const EnvPrefix = "FINN_"
+
+// Config is the server configuration
+type Config struct {
+	// Listen is the TCP address the HTTP server listens on
+	Listen string
+
+	// Database is a SQLite file path or a postgres:// URL
+	Database string
+
+	// StaticDir serves the frontend from a directory instead of the copy
+	// embedded in the binary, for development
+	StaticDir string
+
+	// AllowedOrigins are the browser origins allowed to call the API. Entries
+	// may use a wildcard for subdomains, e.g. https://*.example.com.
+	AllowedOrigins []string
+
+	// CORSAllowCredentials lets browsers send cookies with cross-origin requests
+	CORSAllowCredentials bool
+
+	// CORSMaxAge is how long browsers may cache a CORS preflight response
+	CORSMaxAge time.Duration
+
+	// LogLevel is one of debug, info, warn or error
+	LogLevel string
+
+	// LogFormat is text or json
+	LogFormat string
+
+	// RetainAfterLastDate purges events this long after their last date option
+	RetainAfterLastDate time.Duration
+
+	// RetainAfterFinalized purges events this long after they were finalized
+	RetainAfterFinalized time.Duration
+
+	// JanitorInterval is how often expired events are purged
+	JanitorInterval time.Duration
+
+	// ShutdownTimeout is how long in-flight requests may take to finish when
+	// the server is stopped
+	ShutdownTimeout time.Duration
+
+	// RateLimit is the sustained number of requests per second allowed from
+	// one client; 0 disables rate limiting
+	RateLimit float64
+
+	// RateBurst is how many requests a client may make at once before the
+	// rate limit applies
+	RateBurst int
+
+	// EventRateLimit is the sustained number of responses per second one
+	// event accepts from all clients together; 0 disables it
+	EventRateLimit float64
+
+	// EventRateBurst is how many responses an event accepts at once before
+	// its rate limit applies
+	EventRateBurst int
+
+	// TrustedProxies are the addresses or networks of reverse proxies whose
+	// X-Forwarded-For header names the client
+	TrustedProxies []string
+
+	// MaxBodyBytes caps the size of JSON request bodies
+	MaxBodyBytes int64
+}
+
+// setting describes one configuration value and how to parse it
+type setting struct {
+	name  string
+	usage string
+	set   func(c *Config, value string) error
+	get   func(c *Config) string
+}
+
+// settings lists every configuration value. Defaults are taken from Default.
+var settings = []setting{
+	{
+		name:  "listen",
+		usage: "address the HTTP server listens on",
+		set:   func(c *Config, v string) error { c.Listen = v; return nil },
+		get:   func(c *Config) string { return c.Listen },
+	},
+	{
+		name:  "db",
+		usage: "SQLite database path, or a postgres:// URL to use PostgreSQL",
+		set:   func(c *Config, v string) error { c.Database = v; return nil },
+		get:   func(c *Config) string { return c.Database },
+	},
+	{
+		name:  "static-dir",
+		usage: "serve the frontend from this directory instead of the embedded build, for development",
+		set:   func(c *Config, v string) error { c.StaticDir = v; return nil },
+		get:   func(c *Config) string { return c.StaticDir },
+	},
+	{
+		name:  "allowed-origins",
+		usage: "comma-separated browser origins allowed to call the API, e.g. https://*.example.com",
+		set:   func(c *Config, v string) error { c.AllowedOrigins = splitList(v); return nil },
+		get:   func(c *Config) string { return strings.Join(c.AllowedOrigins, ",") },
+	},
+	{
+		name:  "cors-allow-credentials",
+		usage: "let browsers send cookies with cross-origin requests",
+		set: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return errors.New("must be true or false")
+			}
+			c.CORSAllowCredentials = b
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatBool(c.CORSAllowCredentials) },
+	},
+	{
+		name:  "cors-max-age",
+		usage: "how long browsers may cache a CORS preflight response",
+		set:   durationSetter(func(c *Config) *time.Duration { return &c.CORSMaxAge }),
+		get:   func(c *Config) string { return c.CORSMaxAge.String() },
+	},
+	{
+		name:  "log-level",
+		usage: "minimum log level: debug, info, warn or error",
+		set:   func(c *Config, v string) error { c.LogLevel = strings.ToLower(v); return nil },
+		get:   func(c *Config) string { return c.LogLevel },
+	},
+	{
+		name:  "log-format",
+		usage: "log output format: text or json",
+		set:   func(c *Config, v string) error { c.LogFormat = strings.ToLower(v); return nil },
+		get:   func(c *Config) string { return c.LogFormat },
+	},
+	{
+		name:  "retain-after-last-date",
+		usage: "purge events this long after their last date option (0 disables)",
+		set:   durationSetter(func(c *Config) *time.Duration { return &c.RetainAfterLastDate }),
+		get:   func(c *Config) string { return c.RetainAfterLastDate.String() },
+	},
+	{
+		name:  "retain-after-finalized",
+		usage: "purge events this long after they were finalized (0 disables)",
+		set:   durationSetter(func(c *Config) *time.Duration { return &c.RetainAfterFinalized }),
+		get:   func(c *Config) string { return c.RetainAfterFinalized.String() },
+	},
+	{
+		name:  "janitor-interval",
+		usage: "how often expired events are purged",
+		set:   durationSetter(func(c *Config) *time.Duration { return &c.JanitorInterval }),
+		get:   func(c *Config) string { return c.JanitorInterval.String() },
+	},
+	{
+		name:  "shutdown-timeout",
+		usage: "how long in-flight requests may take to finish on shutdown",
+		set:   durationSetter(func(c *Config) *time.Duration { return &c.ShutdownTimeout }),
+		get:   func(c *Config) string { return c.ShutdownTimeout.String() },
+	},
+	{
+		name:  "rate-limit",
+		usage: "requests per second allowed from one client (0 disables)",
+		set: func(c *Config, v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return errors.New("must be a number")
+			}
+			c.RateLimit = f
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatFloat(c.RateLimit, 'g', -1, 64) },
+	},
+	{
+		name:  "rate-burst",
+		usage: "requests one client may make at once before the rate limit applies",
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return errors.New("must be a whole number")
+			}
+			c.RateBurst = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(c.RateBurst) },
+	},
+	{
+		name:  "event-rate-limit",
+		usage: "responses per second one event accepts from all clients (0 disables)",
+		set: func(c *Config, v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return errors.New("must be a number")
+			}
+			c.EventRateLimit = f
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatFloat(c.EventRateLimit, 'g', -1, 64) },
+	},
+	{
+		name:  "event-rate-burst",
+		usage: "responses one event accepts at once before its rate limit applies",
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return errors.New("must be a whole number")
+			}
+			c.EventRateBurst = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(c.EventRateBurst) },
+	},
+	{
+		name:  "trusted-proxies",
+		usage: "comma-separated proxy addresses or networks whose X-Forwarded-For is trusted, e.g. 10.0.0.0/8",
+		set:   func(c *Config, v string) error { c.TrustedProxies = splitList(v); return nil },
+		get:   func(c *Config) string { return strings.Join(c.TrustedProxies, ",") },
+	},
+	{
+		name:  "max-body-bytes",
+		usage: "largest JSON request body accepted, in bytes",
+		set: func(c *Config, v string) error {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return errors.New("must be a whole number")
+			}
+			c.MaxBodyBytes = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatInt(c.MaxBodyBytes, 10) },
+	},
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() *Config {
+	return &Config{
+		Listen:          ":8080",
+		Database:        "./events.db",
+		AllowedOrigins:  []string{"http://localhost:3000"},
+		CORSMaxAge:      10 * time.Minute,
+		LogLevel:        "info",
+		LogFormat:       "text",
+		JanitorInterval: time.Hour,
+		ShutdownTimeout: 15 * time.Second,
+		RateLimit:       5,
+		RateBurst:       20,
+		EventRateLimit:  2,
+		EventRateBurst:  50,
+		MaxBodyBytes:    64 << 10,
+	}
+}
+
+// Load builds the configuration from command-line arguments, the environment
+// and the optional config file, then validates it. lookupEnv is usually
+// os.LookupEnv. It returns flag.ErrHelp if -h was given.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+
+	// Flags are parsed first to find -config, but applied last
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	envConfigFile, _ := lookupEnv(EnvPrefix + "CONFIG")
+	configFile := fs.String("config", envConfigFile, "path to a config file (env "+EnvPrefix+"CONFIG)")
+	flagValues := make(map[string]*string)
+	for _, s := range settings {
+		flagValues[s.name] = fs.String(s.name, s.get(cfg), s.usage+" (env "+envName(s.name)+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	// Config file
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// Environment variables
+	for _, s := range settings {
+		if v, ok := lookupEnv(envName(s.name)); ok {
+			if err := s.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("%s: %w", envName(s.name), err)
+			}
+		}
+	}
+
+	// Flags given on the command line
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		s, ok := lookup(f.Name)
+		if !ok || flagErr != nil {
+			return
+		}
+		if err := s.set(cfg, *flagValues[s.name]); err != nil {
+			flagErr = fmt.Errorf("-%s: %w", s.name, err)
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile applies the settings in a config file
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected name = value", path, lineNo)
+		}
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		s, ok := lookup(name)
+		if !ok {
+			return fmt.Errorf("%s:%d: unknown setting %q", path, lineNo, name)
+		}
+		if err := s.set(c, value); err != nil {
+			return fmt.Errorf("%s:%d: %s: %w", path, lineNo, name, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that the configuration can be used to start the server.
+// It reports every problem at once.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(name, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", name, fmt.Sprintf(format, args...)))
+	}
+
+	if _, _, err := net.SplitHostPort(c.Listen); err != nil {
+		fail("listen", "must be a host:port address such as :8080")
+	}
+	if c.Database == "" {
+		fail("db", "is required")
+	}
+	for _, origin := range c.AllowedOrigins {
+		if !cors.ValidOrigin(origin) {
+			fail("allowed-origins", "%q is not an origin such as https://example.com or https://*.example.com", origin)
+		}
+	}
+	if c.CORSAllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		fail("allowed-origins", "must list origins instead of * when cors-allow-credentials is set")
+	}
+	if c.CORSMaxAge < 0 {
+		fail("cors-max-age", "must not be negative")
+	}
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		fail("log-level", "must be one of debug, info, warn or error")
+	}
+	switch c.LogFormat {
+	case "text", "json":
+	default:
+		fail("log-format", "must be text or json")
+	}
+	if c.RetainAfterLastDate < 0 {
+		fail("retain-after-last-date", "must not be negative")
+	}
+	if c.RetainAfterFinalized < 0 {
+		fail("retain-after-finalized", "must not be negative")
+	}
+	if c.JanitorInterval <= 0 {
+		fail("janitor-interval", "must be positive")
+	}
+	if c.ShutdownTimeout <= 0 {
+		fail("shutdown-timeout", "must be positive")
+	}
+	if c.RateLimit < 0 {
+		fail("rate-limit", "must not be negative")
+	}
+	if c.RateLimit > 0 && c.RateBurst < 1 {
+		fail("rate-burst", "must be at least 1 when rate limiting is enabled")
+	}
+	if c.EventRateLimit < 0 {
+		fail("event-rate-limit", "must not be negative")
+	}
+	if c.EventRateLimit > 0 && c.EventRateBurst < 1 {
+		fail("event-rate-burst", "must be at least 1 when rate limiting is enabled")
+	}
+	if _, err := ratelimit.ParseProxies(c.TrustedProxies); err != nil {
+		fail("trusted-proxies", "%v", err)
+	}
+	if c.MaxBodyBytes <= 0 {
+		fail("max-body-bytes", "must be positive")
+	}
+
+	return errors.Join(errs...)
+}
+
+// durationSetter returns a setter that parses a duration such as 720h
+func durationSetter(field func(c *Config) *time.Duration) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return errors.New("must be a duration such as 30m or 720h")
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+// lookup finds a setting by name
+func lookup(name string) (setting, bool) {
+	for _, s := range settings {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// envName returns the environment variable for a setting
+func envName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCORS(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		wantErr     string
+	}{
+		{"listed origins", []string{"https://app.example.org", "https://*.example.com"}, false, ""},
+		{"listed origins with credentials", []string{"https://app.example.org"}, true, ""},
+		{"every origin", []string{"*"}, false, ""},
+		{"every origin with credentials", []string{"https://app.example.org", "*"}, true, "allowed-origins: must list origins instead of *"},
+		{"invalid origin", []string{"https://example.com/app"}, false, `allowed-origins: "https://example.com/app" is not an origin`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.AllowedOrigins = tt.origins
+			cfg.CORSAllowCredentials = tt.credentials
+
+			err := cfg.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsWildcardWithCredentials(t *testing.T) {
+	env := map[string]string{"FINN_ALLOWED_ORIGINS": "*"}
+	lookupEnv := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	if _, err := Load([]string{"-cors-allow-credentials=true"}, lookupEnv); err == nil {
+		t.Error("Load accepted * together with -cors-allow-credentials")
+	}
+	if _, err := Load(nil, lookupEnv); err != nil {
+		t.Errorf("Load with * and no credentials = %v", err)
+	}
+}
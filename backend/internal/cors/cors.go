@@ -0,0 +1,214 @@
+// Package cors implements Cross-Origin Resource Sharing for the API, so the
+// frontend can call it from another origin.
+package cors
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options configures which cross-origin requests are allowed
+type Options struct {
+	// AllowedOrigins lists origins such as https://example.com. An entry may
+	// use a wildcard for subdomains, e.g. https://*.example.com, and "*"
+	// allows every origin unless AllowCredentials is set.
+	AllowedOrigins []string
+
+	// AllowedHeaders are the request headers clients may send
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers scripts may read
+	ExposedHeaders []string
+
+	// AllowCredentials lets browsers send cookies and HTTP authentication
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+// CORS applies a set of Options to handlers
+type CORS struct {
+	opts           Options
+	allowAll       bool
+	exact          map[string]bool
+	wildcards      []wildcard
+	allowedHeaders map[string]bool
+}
+
+// wildcard is an origin pattern like https://*.example.com split at the *
+type wildcard struct {
+	prefix, suffix string
+}
+
+// New creates a CORS policy. Origins that are not valid patterns are ignored;
+// use ValidOrigin to check configuration up front.
+func New(opts Options) *CORS {
+	c := &CORS{
+		opts:           opts,
+		exact:          make(map[string]bool),
+		allowedHeaders: make(map[string]bool),
+	}
+
+	for _, origin := range opts.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		switch {
+		case !ValidOrigin(origin):
+		case origin == "*":
+			// Reflecting every origin with credentials would let any site
+			// act as the user
+			c.allowAll = !opts.AllowCredentials
+		case strings.Contains(origin, "*"):
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			c.wildcards = append(c.wildcards, wildcard{prefix: prefix, suffix: suffix})
+		default:
+			c.exact[origin] = true
+		}
+	}
+	for _, header := range opts.AllowedHeaders {
+		c.allowedHeaders[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return c
+}
+
+// ValidOrigin reports whether s can be used in AllowedOrigins: "*", a
+// scheme://host[:port] origin, or one whose host starts with "*." to match
+// any subdomain
+func ValidOrigin(s string) bool {
+	if s == "*" {
+		return true
+	}
+
+	scheme, host, ok := strings.Cut(s, "://")
+	if !ok {
+		return false
+	}
+	if rest, ok := strings.CutPrefix(host, "*."); ok {
+		host = rest
+	}
+	if strings.Contains(host, "*") {
+		return false
+	}
+
+	u, err := url.Parse(scheme + "://" + host)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
+
+// Handler wraps a route that accepts the given methods. Preflight requests
+// are answered here; other requests get the CORS response headers and are
+// passed on to next.
+func (c *CORS) Handler(next http.Handler, methods ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		h := w.Header()
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Add("Vary", "Origin")
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			c.preflight(w, r, origin, methods)
+			return
+		}
+
+		h.Add("Vary", "Origin")
+		if origin != "" && c.originAllowed(origin) {
+			c.setOrigin(h, origin)
+			if len(c.opts.ExposedHeaders) > 0 {
+				h.Set("Access-Control-Expose-Headers", strings.Join(c.opts.ExposedHeaders, ", "))
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers an OPTIONS request asking whether the real request may
+// be sent. Anything not allowed gets a response without CORS headers, which
+// the browser treats as a refusal.
+func (c *CORS) preflight(w http.ResponseWriter, r *http.Request, origin string, methods []string) {
+	defer w.WriteHeader(http.StatusNoContent)
+
+	if origin == "" || !c.originAllowed(origin) {
+		return
+	}
+
+	method := r.Header.Get("Access-Control-Request-Method")
+	if !slices.Contains(methods, method) {
+		return
+	}
+
+	requested := requestedHeaders(r)
+	for _, header := range requested {
+		if !c.allowedHeaders[header] {
+			return
+		}
+	}
+
+	h := w.Header()
+	c.setOrigin(h, origin)
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(requested) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+	}
+	if c.opts.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(c.opts.MaxAge.Seconds())))
+	}
+}
+
+// setOrigin sets the headers naming the allowed origin, or "*" when every
+// origin is allowed
+func (c *CORS) setOrigin(h http.Header, origin string) {
+	if c.allowAll {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if c.opts.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// originAllowed checks an Origin header against the allowlist
+func (c *CORS) originAllowed(origin string) bool {
+	if c.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if c.exact[origin] {
+		return true
+	}
+	for _, w := range c.wildcards {
+		if len(origin) > len(w.prefix)+len(w.suffix) &&
+			strings.HasPrefix(origin, w.prefix) && strings.HasSuffix(origin, w.suffix) {
+			// The part matched by * is one or more labels of the host name
+			sub := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+			if !strings.ContainsAny(sub, "/:@") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requestedHeaders parses Access-Control-Request-Headers into canonical names
+func requestedHeaders(r *http.Request) []string {
+	var headers []string
+	for _, value := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, http.CanonicalHeaderKey(header))
+			}
+		}
+	}
+	return headers
+}
@@ -0,0 +1,235 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"*", true},
+		{"https://example.com", true},
+		{"http://localhost:3000", true},
+		{"https://*.example.com", true},
+		{"example.com", false},
+		{"ftp://example.com", false},
+		{"https://example.com/", false},
+		{"https://example.com/path", false},
+		{"https://user@example.com", false},
+		{"https://*example.com", false},
+		{"https://a.*.example.com", false},
+		{"https://*", false},
+	}
+	for _, tt := range tests {
+		if got := ValidOrigin(tt.origin); got != tt.want {
+			t.Errorf("ValidOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestOriginAllowed(t *testing.T) {
+	c := New(Options{AllowedOrigins: []string{"https://app.example.org", "https://*.example.com"}})
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.org", true},
+		{"HTTPS://APP.EXAMPLE.ORG", true},
+		{"http://app.example.org", false},
+		{"https://app.example.org:8443", false},
+		{"https://other.example.org", false},
+		{"https://a.example.com", true},
+		{"https://a.b.example.com", true},
+		// A subdomain of example.com, whatever its labels look like
+		{"https://evil.com.example.com", true},
+		{"https://example.com", false},
+		{"https://.example.com", false},
+		{"https://evilexample.com", false},
+		{"https://example.com.evil.com", false},
+		{"https://a.example.com.evil.com", false},
+		{"https://evil.com/.example.com", false},
+		{"https://evil.com:1@x.example.com", false},
+		{"http://a.example.com", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		if got := c.originAllowed(tt.origin); got != tt.want {
+			t.Errorf("originAllowed(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	opts := Options{
+		AllowedOrigins: []string{"https://app.example.org", "https://*.example.com"},
+		AllowedHeaders: []string{"Content-Type", "X-Admin-Token"},
+		ExposedHeaders: []string{"X-Request-Id"},
+		MaxAge:         10 * time.Minute,
+	}
+
+	tests := []struct {
+		name    string
+		opts    *Options
+		method  string
+		headers map[string]string
+
+		status      int
+		origin      string
+		credentials string
+		methods     string
+		allowHdrs   string
+		maxAge      string
+		expose      string
+		vary        []string
+	}{
+		{
+			name:    "simple request from allowed origin",
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://app.example.org"},
+			status:  http.StatusOK,
+			origin:  "https://app.example.org",
+			expose:  "X-Request-Id",
+			vary:    []string{"Origin"},
+		},
+		{
+			name:    "simple request from wildcard subdomain",
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://team.example.com"},
+			status:  http.StatusOK,
+			origin:  "https://team.example.com",
+			expose:  "X-Request-Id",
+			vary:    []string{"Origin"},
+		},
+		{
+			name:    "simple request from disallowed origin",
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://example.com.evil.com"},
+			status:  http.StatusOK,
+			vary:    []string{"Origin"},
+		},
+		{
+			name:   "same-origin request",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			vary:   []string{"Origin"},
+		},
+		{
+			name:   "preflight from allowed origin",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.org",
+				"Access-Control-Request-Method":  http.MethodPatch,
+				"Access-Control-Request-Headers": "content-type, x-admin-token",
+			},
+			status:    http.StatusNoContent,
+			origin:    "https://app.example.org",
+			methods:   "GET, PATCH",
+			allowHdrs: "Content-Type, X-Admin-Token",
+			maxAge:    "600",
+			vary:      []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "preflight from disallowed origin",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://evilexample.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			status: http.StatusNoContent,
+			vary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "preflight for disallowed method",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://app.example.org",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			status: http.StatusNoContent,
+			vary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "preflight for disallowed header",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.org",
+				"Access-Control-Request-Method":  http.MethodGet,
+				"Access-Control-Request-Headers": "content-type, x-secret",
+			},
+			status: http.StatusNoContent,
+			vary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:    "wildcard origin",
+			opts:    &Options{AllowedOrigins: []string{"*"}},
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://anywhere.test"},
+			status:  http.StatusOK,
+			origin:  "*",
+			vary:    []string{"Origin"},
+		},
+		{
+			name:        "credentials reflect an allowed origin",
+			opts:        &Options{AllowedOrigins: []string{"https://app.example.org"}, AllowCredentials: true},
+			method:      http.MethodGet,
+			headers:     map[string]string{"Origin": "https://app.example.org"},
+			status:      http.StatusOK,
+			origin:      "https://app.example.org",
+			credentials: "true",
+			vary:        []string{"Origin"},
+		},
+		{
+			name:    "credentials never allow every origin",
+			opts:    &Options{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://anywhere.test"},
+			status:  http.StatusOK,
+			vary:    []string{"Origin"},
+		},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := opts
+			if tt.opts != nil {
+				o = *tt.opts
+			}
+			handler := New(o).Handler(next, http.MethodGet, http.MethodPatch)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/events/x", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			h := rec.Header()
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			for _, check := range []struct{ name, got, want string }{
+				{"Access-Control-Allow-Origin", h.Get("Access-Control-Allow-Origin"), tt.origin},
+				{"Access-Control-Allow-Credentials", h.Get("Access-Control-Allow-Credentials"), tt.credentials},
+				{"Access-Control-Allow-Methods", h.Get("Access-Control-Allow-Methods"), tt.methods},
+				{"Access-Control-Allow-Headers", h.Get("Access-Control-Allow-Headers"), tt.allowHdrs},
+				{"Access-Control-Max-Age", h.Get("Access-Control-Max-Age"), tt.maxAge},
+				{"Access-Control-Expose-Headers", h.Get("Access-Control-Expose-Headers"), tt.expose},
+			} {
+				if check.got != check.want {
+					t.Errorf("%s = %q, want %q", check.name, check.got, check.want)
+				}
+			}
+			if got := h.Values("Vary"); !slices.Equal(got, tt.vary) {
+				t.Errorf("Vary = %q, want %q", got, tt.vary)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// dialect holds what differs between the SQL engines the store runs on.
+// Queries are written with ? placeholders and rewritten for engines that
+// number their parameters.
+type dialect struct {
+	name string
+
+	// numberedParams uses $1, $2, ... instead of ?
+	numberedParams bool
+
+	// dateText reads a date column as YYYY-MM-DD text
+	dateText func(column string) string
+
+	// clockText reads a time of day column as HH:MM text
+	clockText func(column string) string
+
+	// timestampBefore compares a timestamp column with a parameter
+	timestampBefore func(column string) string
+
+	// isUniqueViolation reports whether err is a unique constraint violation
+	isUniqueViolation func(err error) bool
+
+	// migrationLock is run first in every migration transaction so servers
+	// starting at the same time migrate one after the other
+	migrationLock string
+
+	// migrations is the engine's own schema history
+	migrations []migration
+}
+
+var sqliteDialect = &dialect{
+	name: "sqlite",
+	// date() keeps the driver from turning the DATE column into a timestamp
+	dateText:        func(column string) string { return "date(" + column + ")" },
+	clockText:       func(column string) string { return column },
+	timestampBefore: func(column string) string { return "datetime(" + column + ") < datetime(?)" },
+	isUniqueViolation: func(err error) bool {
+		var sqliteErr sqlite3.Error
+		return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	},
+	migrations: sqliteMigrations,
+}
+
+var postgresDialect = &dialect{
+	name:            "postgres",
+	numberedParams:  true,
+	dateText:        func(column string) string { return "to_char(" + column + ", 'YYYY-MM-DD')" },
+	clockText:       func(column string) string { return "to_char(" + column + ", 'HH24:MI')" },
+	timestampBefore: func(column string) string { return column + " < ?" },
+	isUniqueViolation: func(err error) bool {
+		var pqErr *pq.Error
+		return errors.As(err, &pqErr) && pqErr.Code == "23505"
+	},
+	// Held until the transaction ends; the key is arbitrary but fixed
+	migrationLock: "SELECT pg_advisory_xact_lock(7163601)",
+	migrations:    postgresMigrations,
+}
+
+// rebind rewrites the ? placeholders in a query for the dialect. None of the
+// queries contain a literal question mark, so no quoting rules are needed.
+func (d *dialect) rebind(query string) string {
+	if !d.numberedParams {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// sqlDB is a database handle that rebinds queries for its dialect. It only
+// offers context-aware calls so every query can be cancelled with the
+// request that caused it.
+type sqlDB struct {
+	raw     *sql.DB
+	dialect *dialect
+}
+
+// ExecContext rebinds and executes a query without returning rows
+func (db *sqlDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.raw.ExecContext(ctx, db.dialect.rebind(query), args...)
+}
+
+// QueryContext rebinds and executes a query that returns rows
+func (db *sqlDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.raw.QueryContext(ctx, db.dialect.rebind(query), args...)
+}
+
+// QueryRowContext rebinds and executes a query that returns at most one row
+func (db *sqlDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.raw.QueryRowContext(ctx, db.dialect.rebind(query), args...)
+}
+
+// BeginTx starts a transaction that rebinds its queries the same way
+func (db *sqlDB) BeginTx(ctx context.Context) (*sqlTx, error) {
+	tx, err := db.raw.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{raw: tx, dialect: db.dialect}, nil
+}
+
+// sqlTx is a transaction that rebinds queries for its dialect
+type sqlTx struct {
+	raw     *sql.Tx
+	dialect *dialect
+}
+
+// ExecContext rebinds and executes a query without returning rows
+func (tx *sqlTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return tx.raw.ExecContext(ctx, tx.dialect.rebind(query), args...)
+}
+
+// QueryContext rebinds and executes a query that returns rows
+func (tx *sqlTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return tx.raw.QueryContext(ctx, tx.dialect.rebind(query), args...)
+}
+
+// QueryRowContext rebinds and executes a query that returns at most one row
+func (tx *sqlTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return tx.raw.QueryRowContext(ctx, tx.dialect.rebind(query), args...)
+}
+
+// Commit commits the transaction
+func (tx *sqlTx) Commit() error {
+	return tx.raw.Commit()
+}
+
+// Rollback aborts the transaction
+func (tx *sqlTx) Rollback() error {
+	return tx.raw.Rollback()
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		query    string
+		postgres string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT id FROM events WHERE id = ?", "SELECT id FROM events WHERE id = $1"},
+		{
+			"INSERT INTO event_dates (event_id, date, start_time, end_time) VALUES (?, ?, ?, ?) RETURNING id",
+			"INSERT INTO event_dates (event_id, date, start_time, end_time) VALUES ($1, $2, $3, $4) RETURNING id",
+		},
+		{
+			"UPDATE events SET name = ?, sequence = sequence + 1, updated_at = ? WHERE id = ?",
+			"UPDATE events SET name = $1, sequence = sequence + 1, updated_at = $2 WHERE id = $3",
+		},
+		{"SELECT ?,?,?,?,?,?,?,?,?,?,?", "SELECT $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11"},
+		{"SELECT 'blåbær' WHERE x = ?", "SELECT 'blåbær' WHERE x = $1"},
+	}
+	for _, tt := range tests {
+		if got := sqliteDialect.rebind(tt.query); got != tt.query {
+			t.Errorf("sqlite rebind(%q) = %q, want it unchanged", tt.query, got)
+		}
+		if got := postgresDialect.rebind(tt.query); got != tt.postgres {
+			t.Errorf("postgres rebind(%q) = %q, want %q", tt.query, got, tt.postgres)
+		}
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	ctx := context.Background()
+	store := newSQLiteStore(t)
+	created, err := store.CreateEvent(ctx, storeEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	insert := `INSERT INTO respondents (event_id, name, created_at) VALUES (?, 'Kari', CURRENT_TIMESTAMP)`
+	if _, err := store.db.ExecContext(ctx, insert, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.db.ExecContext(ctx, insert, created.ID)
+	if !sqliteDialect.isUniqueViolation(err) {
+		t.Errorf("isUniqueViolation(%v) = false for a duplicate name", err)
+	}
+
+	_, err = store.db.ExecContext(ctx, `INSERT INTO respondents (event_id) VALUES (?)`, created.ID)
+	if err == nil || sqliteDialect.isUniqueViolation(err) {
+		t.Errorf("isUniqueViolation(%v) = true for a NOT NULL violation", err)
+	}
+	if sqliteDialect.isUniqueViolation(errors.New("UNIQUE constraint failed")) {
+		t.Error("isUniqueViolation matched an error by its text")
+	}
+
+	if !postgresDialect.isUniqueViolation(&pq.Error{Code: "23505"}) {
+		t.Error("postgres isUniqueViolation missed unique_violation")
+	}
+	if postgresDialect.isUniqueViolation(&pq.Error{Code: "23502"}) {
+		t.Error("postgres isUniqueViolation matched not_null_violation")
+	}
+}
+
+func TestConcurrentMigrate(t *testing.T) {
+	open := func(t *testing.T) func() *SQLStore {
+		path := filepath.Join(t.TempDir(), "events.db")
+		return func() *SQLStore {
+			store, err := Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		}
+	}
+	if dsn := os.Getenv(postgresDSNEnv); dsn != "" {
+		t.Run("postgres", func(t *testing.T) {
+			shared := newPostgresSchema(t, dsn)
+			testConcurrentMigrate(t, func() *SQLStore { return newSQLStore(shared.db.raw, postgresDialect) })
+		})
+	}
+	t.Run("sqlite", func(t *testing.T) {
+		testConcurrentMigrate(t, open(t))
+	})
+}
+
+// testConcurrentMigrate starts several servers' migrations on one database
+// at once and checks that every one of them succeeds
+func testConcurrentMigrate(t *testing.T, open func() *SQLStore) {
+	const servers = 4
+	ctx := context.Background()
+
+	stores := make([]*SQLStore, servers)
+	for i := range stores {
+		stores[i] = open()
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, servers)
+	for i, store := range stores {
+		wg.Go(func() {
+			errs[i] = store.Migrate(ctx)
+		})
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("server %d: Migrate = %v", i, err)
+		}
+	}
+	version, err := stores[0].SchemaVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != stores[0].LatestSchemaVersion() {
+		t.Errorf("SchemaVersion = %d, want %d", version, stores[0].LatestSchemaVersion())
+	}
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEventNotFound is returned when an event does not exist
+	ErrEventNotFound = errors.New("event not found")
+
+	// ErrInvalidAdminToken is returned when an organizer token is missing or wrong
+	ErrInvalidAdminToken = errors.New("invalid admin token")
+
+	// ErrAdminTokenSet is returned when claiming a token for an event that already has one
+	ErrAdminTokenSet = errors.New("event already has an admin token")
+
+	// ErrRespondentExists is returned when a name is already taken and no edit token was given
+	ErrRespondentExists = errors.New("respondent with this name already exists")
+
+	// ErrInvalidEditToken is returned when a respondent edit token does not match
+	ErrInvalidEditToken = errors.New("invalid edit token")
+
+	// ErrDateNotInEvent is returned when an event date does not belong to the event
+	ErrDateNotInEvent = errors.New("event date does not belong to this event")
+
+	// ErrDateNotFound is returned when a date option addressed directly does not exist
+	ErrDateNotFound = errors.New("event date not found")
+
+	// ErrDuplicateResponse is returned when a submission answers the same date twice
+	ErrDuplicateResponse = errors.New("event date answered more than once")
+
+	// ErrEventFinalized is returned when finalizing an event that already is
+	ErrEventFinalized = errors.New("event is already finalized")
+
+	// ErrLastEventDate is returned when removing the only remaining date option
+	ErrLastEventDate = errors.New("an event must keep at least one date option")
+)
+
+// DateNotInEventError reports a response for a date option that is not part
+// of the event. It matches ErrDateNotInEvent with errors.Is.
+type DateNotInEventError struct {
+	EventDateID int
+}
+
+func (e *DateNotInEventError) Error() string {
+	return fmt.Sprintf("event date %d does not belong to this event", e.EventDateID)
+}
+
+func (e *DateNotInEventError) Is(target error) bool {
+	return target == ErrDateNotInEvent
+}
+
+// DuplicateResponseError reports a submission that answers a date option more
+// than once. It matches ErrDuplicateResponse with errors.Is.
+type DuplicateResponseError struct {
+	EventDateID int
+}
+
+func (e *DuplicateResponseError) Error() string {
+	return fmt.Sprintf("event date %d answered more than once", e.EventDateID)
+}
+
+func (e *DuplicateResponseError) Is(target error) bool {
+	return target == ErrDuplicateResponse
+}
+
+// domainErrors are the errors that describe the request rather than a fault
+var domainErrors = []error{
+	ErrEventNotFound,
+	ErrInvalidAdminToken,
+	ErrAdminTokenSet,
+	ErrRespondentExists,
+	ErrInvalidEditToken,
+	ErrDateNotInEvent,
+	ErrDateNotFound,
+	ErrDuplicateResponse,
+	ErrLastEventDate,
+	ErrEventFinalized,
+}
+
+// isFault reports whether err is an unexpected failure worth logging, as
+// opposed to a domain error or a request the client gave up on
+func isFault(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	for _, domainErr := range domainErrors {
+		if errors.Is(err, domainErr) {
+			return false
+		}
+	}
+	return true
+}
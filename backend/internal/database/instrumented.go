@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/metrics"
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// instrumentedStore records the latency of every operation of the store it
+// wraps, counts the ones that matter to the business and logs failures with
+// the request ID from the context
+type instrumentedStore struct {
+	next EventStore
+}
+
+// Instrument wraps a store so its operations are reported as metrics and
+// unexpected failures are logged
+func Instrument(store EventStore) EventStore {
+	return &instrumentedStore{next: store}
+}
+
+// observe records how long an operation took since start and logs it if it
+// failed unexpectedly. Call it deferred with a pointer to the named error.
+func observe(ctx context.Context, operation string, start time.Time, err *error) {
+	elapsed := time.Since(start)
+	metrics.DBOperationDuration.WithLabelValues(operation).Observe(elapsed.Seconds())
+
+	if isFault(*err) {
+		slog.ErrorContext(ctx, "database operation failed", "operation", operation, "duration", elapsed, "error", *err)
+	}
+}
+
+func (s *instrumentedStore) CreateEvent(ctx context.Context, req models.CreateEventRequest) (event *models.CreateEventResponse, err error) {
+	defer observe(ctx, "CreateEvent", time.Now(), &err)
+	event, err = s.next.CreateEvent(ctx, req)
+	if err == nil {
+		metrics.EventsCreated.Inc()
+	}
+	return event, err
+}
+
+func (s *instrumentedStore) GetEvent(ctx context.Context, eventID string) (event *models.Event, err error) {
+	defer observe(ctx, "GetEvent", time.Now(), &err)
+	return s.next.GetEvent(ctx, eventID)
+}
+
+func (s *instrumentedStore) UpdateEventName(ctx context.Context, eventID string, name string) (err error) {
+	defer observe(ctx, "UpdateEventName", time.Now(), &err)
+	return s.next.UpdateEventName(ctx, eventID, name)
+}
+
+func (s *instrumentedStore) AddEventDate(ctx context.Context, eventID string, req models.CreateDateRequest) (date *models.EventDate, err error) {
+	defer observe(ctx, "AddEventDate", time.Now(), &err)
+	return s.next.AddEventDate(ctx, eventID, req)
+}
+
+func (s *instrumentedStore) RemoveEventDate(ctx context.Context, eventID string, eventDateID int) (err error) {
+	defer observe(ctx, "RemoveEventDate", time.Now(), &err)
+	return s.next.RemoveEventDate(ctx, eventID, eventDateID)
+}
+
+func (s *instrumentedStore) DeleteEvent(ctx context.Context, eventID string) (err error) {
+	defer observe(ctx, "DeleteEvent", time.Now(), &err)
+	return s.next.DeleteEvent(ctx, eventID)
+}
+
+func (s *instrumentedStore) FinalizeEvent(ctx context.Context, eventID string, eventDateID int) (err error) {
+	defer observe(ctx, "FinalizeEvent", time.Now(), &err)
+	err = s.next.FinalizeEvent(ctx, eventID, eventDateID)
+	if err == nil {
+		metrics.EventsFinalized.Inc()
+	}
+	return err
+}
+
+func (s *instrumentedStore) VerifyAdminToken(ctx context.Context, eventID string, token string) (err error) {
+	defer observe(ctx, "VerifyAdminToken", time.Now(), &err)
+	return s.next.VerifyAdminToken(ctx, eventID, token)
+}
+
+func (s *instrumentedStore) SubmitResponse(ctx context.Context, eventID string, req models.SubmitResponseRequest, editToken string) (result *models.SubmitResponseResult, err error) {
+	defer observe(ctx, "SubmitResponse", time.Now(), &err)
+	result, err = s.next.SubmitResponse(ctx, eventID, req, editToken)
+	if err == nil {
+		metrics.ResponsesSubmitted.Inc()
+	}
+	return result, err
+}
+
+func (s *instrumentedStore) GetEventResults(ctx context.Context, eventID string) (results *models.EventResults, err error) {
+	defer observe(ctx, "GetEventResults", time.Now(), &err)
+	return s.next.GetEventResults(ctx, eventID)
+}
+
+func (s *instrumentedStore) PurgeExpiredEvents(ctx context.Context, policy RetentionPolicy, now time.Time) (purged int, err error) {
+	defer observe(ctx, "PurgeExpiredEvents", time.Now(), &err)
+	return s.next.PurgeExpiredEvents(ctx, policy, now)
+}
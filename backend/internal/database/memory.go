@@ -0,0 +1,423 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// MemoryStore is an EventStore that keeps everything in memory. It is meant
+// for tests and local experiments; nothing survives a restart.
+type MemoryStore struct {
+	mu               sync.RWMutex
+	events           map[string]*memoryEvent
+	nextDateID       int
+	nextRespondentID int
+	nextResponseID   int
+}
+
+// memoryEvent is an event together with the data that is never returned directly
+type memoryEvent struct {
+	event          models.Event
+	adminTokenHash string
+	respondents    []*memoryRespondent
+}
+
+// memoryRespondent is a respondent together with their edit token hash
+type memoryRespondent struct {
+	respondent    models.Respondent
+	editTokenHash string
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{events: make(map[string]*memoryEvent)}
+}
+
+// CreateEvent creates a new event with its associated dates
+func (s *MemoryStore) CreateEvent(ctx context.Context, req models.CreateEventRequest) (*models.CreateEventResponse, error) {
+	adminToken, adminTokenHash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		timeZone = models.DefaultTimeZone
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := models.Event{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		TimeZone:  timeZone,
+		CreatedAt: time.Now(),
+	}
+	for _, dateReq := range req.Dates {
+		s.nextDateID++
+		event.Dates = append(event.Dates, models.EventDate{
+			ID:        s.nextDateID,
+			EventID:   event.ID,
+			Date:      dateReq.Date,
+			StartTime: dateReq.StartTime,
+			EndTime:   dateReq.EndTime,
+		})
+	}
+
+	s.events[event.ID] = &memoryEvent{event: event, adminTokenHash: adminTokenHash}
+
+	// Return the dates in request order, like the SQLite store does
+	created := event
+	created.Dates = append([]models.EventDate(nil), event.Dates...)
+	if err := localizeInEventZone(&created); err != nil {
+		return nil, err
+	}
+
+	return &models.CreateEventResponse{Event: created, AdminToken: adminToken}, nil
+}
+
+// GetEvent retrieves an event by ID with its dates
+func (s *MemoryStore) GetEvent(ctx context.Context, eventID string) (*models.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+
+	event := copyEvent(e.event)
+	if err := localizeInEventZone(&event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// UpdateEventName renames an event
+func (s *MemoryStore) UpdateEventName(ctx context.Context, eventID string, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return ErrEventNotFound
+	}
+
+	e.event.Name = name
+	e.touch()
+	return nil
+}
+
+// AddEventDate adds a new date option to an existing event
+func (s *MemoryStore) AddEventDate(ctx context.Context, eventID string, req models.CreateDateRequest) (*models.EventDate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+
+	s.nextDateID++
+	date := models.EventDate{
+		ID:        s.nextDateID,
+		EventID:   eventID,
+		Date:      req.Date,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+	e.event.Dates = append(e.event.Dates, date)
+	e.touch()
+
+	event := models.Event{ID: eventID, TimeZone: e.event.TimeZone, Dates: []models.EventDate{date}}
+	if err := localizeInEventZone(&event); err != nil {
+		return nil, err
+	}
+
+	return &event.Dates[0], nil
+}
+
+// RemoveEventDate removes a date option from an event together with the
+// responses pointing at it
+func (s *MemoryStore) RemoveEventDate(ctx context.Context, eventID string, eventDateID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return ErrEventNotFound
+	}
+	if e.dateIndex(eventDateID) < 0 {
+		return ErrDateNotFound
+	}
+	if len(e.event.Dates) <= 1 {
+		return ErrLastEventDate
+	}
+
+	for _, r := range e.respondents {
+		kept := r.respondent.Responses[:0]
+		for _, response := range r.respondent.Responses {
+			if response.EventDateID != eventDateID {
+				kept = append(kept, response)
+			}
+		}
+		r.respondent.Responses = kept
+	}
+
+	if e.event.FinalizedDateID != nil && *e.event.FinalizedDateID == eventDateID {
+		e.event.FinalizedDateID = nil
+		e.event.FinalizedAt = nil
+	}
+
+	i := e.dateIndex(eventDateID)
+	e.event.Dates = append(e.event.Dates[:i], e.event.Dates[i+1:]...)
+	e.touch()
+	return nil
+}
+
+// DeleteEvent deletes an event together with its dates, respondents and responses
+func (s *MemoryStore) DeleteEvent(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[eventID]; !ok {
+		return ErrEventNotFound
+	}
+
+	delete(s.events, eventID)
+	return nil
+}
+
+// FinalizeEvent sets the finalized date for an event
+func (s *MemoryStore) FinalizeEvent(ctx context.Context, eventID string, eventDateID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return ErrEventNotFound
+	}
+	if e.event.FinalizedDateID != nil {
+		return ErrEventFinalized
+	}
+	if e.dateIndex(eventDateID) < 0 {
+		return &DateNotInEventError{EventDateID: eventDateID}
+	}
+
+	now := time.Now()
+	e.event.FinalizedDateID = &eventDateID
+	e.event.FinalizedAt = &now
+	e.touch()
+	return nil
+}
+
+// VerifyAdminToken checks that token is the organizer token for an event
+func (s *MemoryStore) VerifyAdminToken(ctx context.Context, eventID string, token string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return ErrEventNotFound
+	}
+	if !tokenMatches(token, e.adminTokenHash) {
+		return ErrInvalidAdminToken
+	}
+
+	return nil
+}
+
+// SubmitResponse submits a respondent's availability responses
+func (s *MemoryStore) SubmitResponse(ctx context.Context, eventID string, req models.SubmitResponseRequest, editToken string) (*models.SubmitResponseResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+	answered := make(map[int]bool)
+	for _, response := range req.Responses {
+		if e.dateIndex(response.EventDateID) < 0 {
+			return nil, &DateNotInEventError{EventDateID: response.EventDateID}
+		}
+		if answered[response.EventDateID] {
+			return nil, &DuplicateResponseError{EventDateID: response.EventDateID}
+		}
+		answered[response.EventDateID] = true
+	}
+
+	// Find or create the respondent
+	var respondent *memoryRespondent
+	var newEditToken string
+	for _, r := range e.respondents {
+		if r.respondent.Name == req.Name {
+			respondent = r
+			break
+		}
+	}
+	if respondent == nil {
+		token, hash, err := newToken()
+		if err != nil {
+			return nil, err
+		}
+		newEditToken = token
+
+		s.nextRespondentID++
+		respondent = &memoryRespondent{
+			respondent: models.Respondent{
+				ID:        s.nextRespondentID,
+				EventID:   eventID,
+				Name:      req.Name,
+				CreatedAt: time.Now(),
+			},
+			editTokenHash: hash,
+		}
+		e.respondents = append(e.respondents, respondent)
+	} else if editToken == "" {
+		return nil, ErrRespondentExists
+	} else if !tokenMatches(editToken, respondent.editTokenHash) {
+		return nil, ErrInvalidEditToken
+	}
+
+	// Replace the respondent's responses
+	respondent.respondent.Responses = nil
+	for _, response := range req.Responses {
+		s.nextResponseID++
+		availability := response.ResolvedAvailability()
+		respondent.respondent.Responses = append(respondent.respondent.Responses, models.Response{
+			ID:           s.nextResponseID,
+			RespondentID: respondent.respondent.ID,
+			EventDateID:  response.EventDateID,
+			Availability: availability,
+			Available:    availability == models.AvailabilityYes,
+		})
+	}
+
+	return &models.SubmitResponseResult{
+		RespondentID: respondent.respondent.ID,
+		EditToken:    newEditToken,
+	}, nil
+}
+
+// GetEventResults gets aggregated results for an event
+func (s *MemoryStore) GetEventResults(ctx context.Context, eventID string) (*models.EventResults, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+
+	event := copyEvent(e.event)
+	if err := localizeInEventZone(&event); err != nil {
+		return nil, err
+	}
+
+	var respondents []models.Respondent
+	for _, r := range e.respondents {
+		respondent := r.respondent
+		if len(r.respondent.Responses) > 0 {
+			respondent.Responses = append([]models.Response(nil), r.respondent.Responses...)
+		} else {
+			respondent.Responses = nil
+		}
+		respondents = append(respondents, respondent)
+	}
+
+	return buildEventResults(&event, respondents), nil
+}
+
+// PurgeExpiredEvents deletes every event that has expired under the policy
+func (s *MemoryStore) PurgeExpiredEvents(ctx context.Context, policy RetentionPolicy, now time.Time) (int, error) {
+	if !policy.Enabled() {
+		return 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lastDateCutoff := now.Add(-policy.AfterLastDate).Format("2006-01-02")
+	finalizedCutoff := now.Add(-policy.AfterFinalized)
+
+	purged := 0
+	for eventID, e := range s.events {
+		expired := false
+
+		if policy.AfterLastDate > 0 && len(e.event.Dates) > 0 {
+			lastDate := ""
+			for _, date := range e.event.Dates {
+				if date.Date > lastDate {
+					lastDate = date.Date
+				}
+			}
+			expired = lastDate < lastDateCutoff
+		}
+		if policy.AfterFinalized > 0 && e.event.FinalizedAt != nil && e.event.FinalizedAt.Before(finalizedCutoff) {
+			expired = true
+		}
+
+		if expired {
+			delete(s.events, eventID)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// touch records that an event changed
+func (e *memoryEvent) touch() {
+	now := time.Now()
+	e.event.Sequence++
+	e.event.UpdatedAt = &now
+}
+
+// dateIndex returns the position of a date option in the event, or -1
+func (e *memoryEvent) dateIndex(eventDateID int) int {
+	for i, date := range e.event.Dates {
+		if date.ID == eventDateID {
+			return i
+		}
+	}
+	return -1
+}
+
+// copyEvent returns a copy of an event that shares no memory with the
+// stored one, with its dates in the same order the SQLite store uses
+func copyEvent(event models.Event) models.Event {
+	if len(event.Dates) > 0 {
+		event.Dates = append([]models.EventDate(nil), event.Dates...)
+	} else {
+		event.Dates = nil
+	}
+	sort.SliceStable(event.Dates, func(i, j int) bool {
+		if event.Dates[i].Date != event.Dates[j].Date {
+			return event.Dates[i].Date < event.Dates[j].Date
+		}
+		return event.Dates[i].StartTime < event.Dates[j].StartTime
+	})
+
+	if event.FinalizedDateID != nil {
+		id := *event.FinalizedDateID
+		event.FinalizedDateID = &id
+	}
+	if event.FinalizedAt != nil {
+		at := *event.FinalizedAt
+		event.FinalizedAt = &at
+	}
+	if event.UpdatedAt != nil {
+		at := *event.UpdatedAt
+		event.UpdatedAt = &at
+	}
+
+	return event
+}
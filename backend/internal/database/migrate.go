@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer build
+var ErrSchemaTooNew = errors.New("database schema is newer than this build supports")
+
+// migration is a single numbered schema change
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// execSQL returns a migration step that runs a block of SQL statements
+func execSQL(query string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// uniqueRespondentNames makes names unique per event, so two first
+// submissions under the same name cannot both create a respondent. Names
+// duplicated before this get the respondent ID appended, e.g. "Ola (12)",
+// keeping the oldest respondent's name as it was.
+const uniqueRespondentNames = `
+		UPDATE respondents SET name = name || ' (' || id || ')'
+		WHERE id NOT IN (SELECT MIN(id) FROM respondents GROUP BY event_id, name);
+		CREATE UNIQUE INDEX respondents_event_id_name_idx ON respondents (event_id, name);
+		`
+
+// LatestSchemaVersion returns the schema version this build migrates to
+func (s *SQLStore) LatestSchemaVersion() int {
+	return s.dialect.migrations[len(s.dialect.migrations)-1].version
+}
+
+// SchemaVersion returns the version the database is currently migrated to.
+// A database that has never been migrated reports version 0.
+func (s *SQLStore) SchemaVersion(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	version, err := s.lockedVersion(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return version, nil
+}
+
+// Migrate brings the database schema up to date. Each pending migration runs
+// in its own transaction together with the schema_version bookkeeping, so a
+// failed migration leaves the database at the last good version. SQLite and
+// PostgreSQL each have their own list of migrations.
+//
+// Several servers may start against the same database at once. Every
+// transaction first takes the dialect's migration lock and re-reads the
+// version, so each migration is applied by exactly one of them.
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	for _, m := range s.dialect.migrations {
+		if err := s.applyMigration(ctx, m); err != nil {
+			if errors.Is(err, ErrSchemaTooNew) {
+				return err
+			}
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs a single migration and records it, unless the database
+// already has it
+func (s *SQLStore) applyMigration(ctx context.Context, m migration) error {
+	// Start transaction
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := s.lockedVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	latest := s.LatestSchemaVersion()
+	if current > latest {
+		return fmt.Errorf("%w: database is at version %d, build supports up to %d", ErrSchemaTooNew, current, latest)
+	}
+	if m.version <= current {
+		return nil
+	}
+
+	if err := m.up(tx.raw); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO schema_version (version, name, applied_at)
+		VALUES (?, ?, ?)
+	`, m.version, m.name, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockedVersion takes the migration lock for the rest of tx, creates the
+// schema_version table if needed and returns the current version
+func (s *SQLStore) lockedVersion(ctx context.Context, tx *sqlTx) (int, error) {
+	if s.dialect.migrationLock != "" {
+		if _, err := tx.ExecContext(ctx, s.dialect.migrationLock); err != nil {
+			return 0, fmt.Errorf("failed to take migration lock: %w", err)
+		}
+	}
+
+	if err := createVersionTable(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	var version sql.NullInt64
+	err := tx.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return int(version.Int64), nil
+}
+
+// createVersionTable creates the schema_version bookkeeping table
+func createVersionTable(ctx context.Context, tx *sqlTx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+	return nil
+}
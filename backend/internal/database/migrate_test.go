@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// legacyFixture is a database created before the migration subsystem, at
+// schema version 0
+const legacyFixture = "../../data/events.db"
+
+// openLegacy copies the version 0 fixture, seeds it the way the original
+// server wrote rows and opens it as a store
+func openLegacy(t *testing.T) *SQLStore {
+	t.Helper()
+	data, err := os.ReadFile(legacyFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "events.db")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	_, err = store.db.raw.Exec(`
+		INSERT INTO events (id, name, created_at) VALUES ('legacy', 'Sommerfest', '2024-05-01 12:00:00');
+		INSERT INTO event_dates (id, event_id, date, start_time, end_time) VALUES
+			(1, 'legacy', '2024-06-21', '18:00', '23:00'),
+			(2, 'legacy', '2024-06-22', '18:00', '23:00');
+		INSERT INTO respondents (id, event_id, name) VALUES
+			(1, 'legacy', 'Kari'),
+			(2, 'legacy', 'Ola'),
+			(3, 'legacy', 'Ola');
+		INSERT INTO responses (respondent_id, event_date_id, available) VALUES
+			(1, 1, 1), (1, 2, 0),
+			(2, 1, 0), (2, 2, 1),
+			(3, 1, 1);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed fixture: %v", err)
+	}
+	return store
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	ctx := context.Background()
+	store := openLegacy(t)
+
+	version, err := store.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 {
+		t.Fatalf("fixture is at version %d, want 0", version)
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	version, err = store.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != store.LatestSchemaVersion() {
+		t.Errorf("SchemaVersion = %d, want %d", version, store.LatestSchemaVersion())
+	}
+	if err := store.Ready(ctx); err != nil {
+		t.Errorf("Ready after migrating = %v", err)
+	}
+
+	// Running again finds nothing to do
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	results, err := store.GetEventResults(ctx, "legacy")
+	if err != nil {
+		t.Fatalf("GetEventResults: %v", err)
+	}
+	if results.Event.TimeZone != models.DefaultTimeZone {
+		t.Errorf("time zone = %q, want %q", results.Event.TimeZone, models.DefaultTimeZone)
+	}
+
+	// The boolean answers became yes and no, and the duplicated name got
+	// the respondent ID appended
+	want := map[string]map[int]models.Availability{
+		"Kari":    {1: models.AvailabilityYes, 2: models.AvailabilityNo},
+		"Ola":     {1: models.AvailabilityNo, 2: models.AvailabilityYes},
+		"Ola (3)": {1: models.AvailabilityYes},
+	}
+	if len(results.Respondents) != len(want) {
+		t.Fatalf("got %d respondents, want %d", len(results.Respondents), len(want))
+	}
+	for _, respondent := range results.Respondents {
+		answers, ok := want[respondent.Name]
+		if !ok {
+			t.Errorf("unexpected respondent %q", respondent.Name)
+			continue
+		}
+		if len(respondent.Responses) != len(answers) {
+			t.Errorf("%s has %d responses, want %d", respondent.Name, len(respondent.Responses), len(answers))
+		}
+		for _, response := range respondent.Responses {
+			if got := response.Availability; got != answers[response.EventDateID] {
+				t.Errorf("%s on date %d = %q, want %q", respondent.Name, response.EventDateID, got, answers[response.EventDateID])
+			}
+		}
+	}
+}
+
+func TestMigrateSchemaTooNew(t *testing.T) {
+	ctx := context.Background()
+	store := newSQLiteStore(t)
+
+	future := store.LatestSchemaVersion() + 1
+	_, err := store.db.ExecContext(ctx, `
+		INSERT INTO schema_version (version, name) VALUES (?, 'from a newer build')
+	`, future)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Migrate(ctx)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("Migrate = %v, want ErrSchemaTooNew", err)
+	}
+	if version, _ := store.SchemaVersion(ctx); version != future {
+		t.Errorf("SchemaVersion = %d, want %d", version, future)
+	}
+	if err := store.Ready(ctx); err == nil {
+		t.Error("Ready succeeded on a database from a newer build")
+	}
+}
+
+func TestMigrateFailureKeepsLastVersion(t *testing.T) {
+	ctx := context.Background()
+
+	// The first three SQLite migrations, then one that changes the schema
+	// before failing
+	broken := *sqliteDialect
+	broken.migrations = append(sqliteMigrations[:3:3], migration{
+		version: 4,
+		name:    "broken",
+		up: func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`CREATE TABLE half_done (id INTEGER)`); err != nil {
+				return err
+			}
+			return errors.New("something went wrong")
+		},
+	})
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newSQLStore(db, &broken)
+	t.Cleanup(func() { store.Close() })
+
+	err = store.Migrate(ctx)
+	if err == nil {
+		t.Fatal("Migrate succeeded with a failing migration")
+	}
+	if want := "migration 4 (broken) failed: something went wrong"; err.Error() != want {
+		t.Errorf("Migrate = %q, want %q", err, want)
+	}
+
+	version, err := store.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 3 {
+		t.Errorf("SchemaVersion = %d, want 3", version)
+	}
+
+	// The failed migration's changes were rolled back with it
+	var tables int
+	err = db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'half_done'`).Scan(&tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Error("the failed migration's table was kept")
+	}
+}
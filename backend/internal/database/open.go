@@ -0,0 +1,54 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Open connects to the database named by dsn and returns a store for it.
+// postgres:// and postgresql:// URLs select PostgreSQL; anything else is
+// taken as a SQLite file path or file: URI. The schema is not touched;
+// call Migrate before serving requests.
+func Open(dsn string) (*SQLStore, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open postgres database: %w", err)
+		}
+		return NewPostgresStore(db), nil
+	}
+
+	db, err := sql.Open("sqlite3", sqliteDSN(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	return NewSQLiteStore(db), nil
+}
+
+// sqliteDSN makes SQLite wait on locks instead of failing, so the janitor
+// can write alongside requests, unless the DSN sets its own busy timeout. It
+// also starts transactions with BEGIN IMMEDIATE: a transaction that reads
+// before it writes would otherwise fail at once with "database is locked"
+// when another one is writing, instead of waiting its turn.
+func sqliteDSN(dsn string) string {
+	// Matches both _busy_timeout and its alias _timeout
+	if !strings.Contains(dsn, "_timeout=") {
+		dsn = withParam(dsn, "_busy_timeout=5000")
+	}
+	if !strings.Contains(dsn, "_txlock=") {
+		dsn = withParam(dsn, "_txlock=immediate")
+	}
+	return dsn
+}
+
+// withParam adds a query parameter to a DSN
+func withParam(dsn, param string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
@@ -0,0 +1,19 @@
+package database
+
+import "testing"
+
+func TestSQLiteDSN(t *testing.T) {
+	tests := []struct {
+		dsn, want string
+	}{
+		{"./events.db", "./events.db?_busy_timeout=5000&_txlock=immediate"},
+		{"file:events.db?cache=shared", "file:events.db?cache=shared&_busy_timeout=5000&_txlock=immediate"},
+		{"events.db?_timeout=100", "events.db?_timeout=100&_txlock=immediate"},
+		{"events.db?_busy_timeout=100&_txlock=deferred", "events.db?_busy_timeout=100&_txlock=deferred"},
+	}
+	for _, tt := range tests {
+		if got := sqliteDSN(tt.dsn); got != tt.want {
+			t.Errorf("sqliteDSN(%q) = %q, want %q", tt.dsn, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"time"
 	"fmt"
@@ -9,23 +10,35 @@ import (
 	"github.com/jleikdra/finn-en-dato/backend/internal/models"
 )
 
-// CreateEvent creates a new event with its associated dates
-func CreateEvent(db *sql.DB, req models.CreateEventRequest) (*models.Event, error) {
+// CreateEvent creates a new event with its associated dates. The returned
+// admin token is only available here; the database stores its hash.
+func (s *SQLStore) CreateEvent(ctx context.Context, req models.CreateEventRequest) (*models.CreateEventResponse, error) {
 	// Generate UUID for event
 	eventID := uuid.New().String()
 
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		timeZone = models.DefaultTimeZone
+	}
+
+	// Generate organizer token
+	adminToken, adminTokenHash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
 	// Start transaction
-	tx, err := db.Begin()
+	tx, err := s.db.BeginTx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	// Insert event
-	_, err = tx.Exec(`
-		INSERT INTO events (id, name, created_at)
-		VALUES (?, ?, ?)
-	`, eventID, req.Name, time.Now())
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO events (id, name, time_zone, created_at, admin_token_hash)
+		VALUES (?, ?, ?, ?, ?)
+	`, eventID, req.Name, timeZone, time.Now(), adminTokenHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert event: %w", err)
 	}
@@ -33,22 +46,19 @@ func CreateEvent(db *sql.DB, req models.CreateEventRequest) (*models.Event, erro
 	// Insert event dates
 	var dates []models.EventDate
 	for _, dateReq := range req.Dates {
-		result, err := tx.Exec(`
+		var dateID int
+		err := tx.QueryRowContext(ctx, `
 			INSERT INTO event_dates (event_id, date, start_time, end_time)
 			VALUES (?, ?, ?, ?)
-		`, eventID, dateReq.Date, dateReq.StartTime, dateReq.EndTime)
+			RETURNING id
+		`, eventID, dateReq.Date, dateReq.StartTime, dateReq.EndTime).Scan(&dateID)
 
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert event date: %w", err)
 		}
 
-		dateID, err := result.LastInsertId()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get last insert id: %w", err)
-		}
-
 		dates = append(dates, models.EventDate{
-			ID:        int(dateID),
+			ID:        dateID,
 			EventID:   eventID,
 			Date:      dateReq.Date,
 			StartTime: dateReq.StartTime,
@@ -62,30 +72,39 @@ func CreateEvent(db *sql.DB, req models.CreateEventRequest) (*models.Event, erro
 	}
 
 	// Return created event
-	event := &models.Event{
+	event := models.Event{
 		ID:        eventID,
 		Name:      req.Name,
+		TimeZone:  timeZone,
 		CreatedAt: time.Now(),
 		Dates:     dates,
 	}
+	if err := localizeInEventZone(&event); err != nil {
+		return nil, err
+	}
 
-	return event, nil
+	return &models.CreateEventResponse{Event: event, AdminToken: adminToken}, nil
 }
 
 // GetEvent retrieves an event by ID with its dates
-func GetEvent(db *sql.DB, eventID string) (*models.Event, error) {
+func (s *SQLStore) GetEvent(ctx context.Context, eventID string) (*models.Event, error) {
 	// Get event details
 	var event models.Event
 	var createdAt string
 	var finalizedDateID sql.NullInt64
+	var finalizedAt sql.NullTime
+	var updatedAt sql.NullTime
 
-	err := db.QueryRow(`
-		SELECT id, name, created_at, finalized_date_id
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, name, time_zone, created_at, finalized_date_id, finalized_at, sequence, updated_at
 		FROM events WHERE id = ?
-	`, eventID).Scan(&event.ID, &event.Name, &createdAt, &finalizedDateID)
+	`, eventID).Scan(&event.ID, &event.Name, &event.TimeZone, &createdAt, &finalizedDateID, &finalizedAt, &event.Sequence, &updatedAt)
 
+	if err == sql.ErrNoRows {
+		return nil, ErrEventNotFound
+	}
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get event: %w", err)
 	}
 
 	// Parse created_at timestamp - SQLite stores it in RFC3339 format
@@ -103,10 +122,16 @@ func GetEvent(db *sql.DB, eventID string) (*models.Event, error) {
 		id := int(finalizedDateID.Int64)
 		event.FinalizedDateID = &id
 	}
+	if finalizedAt.Valid {
+		event.FinalizedAt = &finalizedAt.Time
+	}
+	if updatedAt.Valid {
+		event.UpdatedAt = &updatedAt.Time
+	}
 
-	// Get event dates
-	rows, err := db.Query(`
-		SELECT id, event_id, date, start_time, end_time
+	// Get event dates as YYYY-MM-DD and HH:MM text
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, event_id, `+s.dialect.dateText("date")+`, `+s.dialect.clockText("start_time")+`, `+s.dialect.clockText("end_time")+`
 		FROM event_dates WHERE event_id = ?
 		ORDER BY date, start_time
 	`, eventID)
@@ -126,125 +151,175 @@ func GetEvent(db *sql.DB, eventID string) (*models.Event, error) {
 	}
 
 	event.Dates = dates
+	if err := localizeInEventZone(&event); err != nil {
+		return nil, err
+	}
+
 	return &event, nil
 }
 
-// SubmitResponse submits a respondent's availability responses
-func SubmitResponse(db *sql.DB, eventID string, req models.SubmitResponseRequest) error {
+// localizeInEventZone fills in the date options' instants in the event's own time zone
+func localizeInEventZone(event *models.Event) error {
+	loc, err := event.Location()
+	if err != nil {
+		return fmt.Errorf("invalid event time zone %q: %w", event.TimeZone, err)
+	}
+	return event.Localize(loc)
+}
+
+// SubmitResponse submits a respondent's availability responses. The first
+// submission for a name creates the respondent and returns a new edit token;
+// later submissions must present that token to replace the responses.
+func (s *SQLStore) SubmitResponse(ctx context.Context, eventID string, req models.SubmitResponseRequest, editToken string) (*models.SubmitResponseResult, error) {
 	// Start transaction
-	tx, err := db.Begin()
+	tx, err := s.db.BeginTx(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	// Verify the event and the referenced dates
+	if err := verifyResponseDates(ctx, tx, eventID, req.Responses); err != nil {
+		return nil, err
+	}
+
 	// Check if respondent already exists
 	var respondentID int64
-	err = tx.QueryRow(`
-		SELECT id FROM respondents WHERE event_id = ? AND name = ?
-	`, eventID, req.Name).Scan(&respondentID)
+	var storedHash sql.NullString
+	var newEditToken string
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, edit_token_hash FROM respondents WHERE event_id = ? AND name = ?
+	`, eventID, req.Name).Scan(&respondentID, &storedHash)
 
 	if err == sql.ErrNoRows {
-		// Insert new respondent
-		result, err := tx.Exec(`
-			INSERT INTO respondents (event_id, name, created_at)
-			VALUES (?, ?, ?)
-		`, eventID, req.Name, time.Now())
-
+		// Generate edit token for the new respondent
+		var editTokenHash string
+		newEditToken, editTokenHash, err = newToken()
 		if err != nil {
-			return fmt.Errorf("failed to insert respondent: %w", err)
+			return nil, err
 		}
 
-		respondentID, err = result.LastInsertId()
+		// Insert new respondent
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO respondents (event_id, name, created_at, edit_token_hash)
+			VALUES (?, ?, ?, ?)
+			RETURNING id
+		`, eventID, req.Name, time.Now(), editTokenHash).Scan(&respondentID)
+
+		// Someone else took the name since the check above
+		if err != nil && s.dialect.isUniqueViolation(err) {
+			return nil, ErrRespondentExists
+		}
 		if err != nil {
-			return fmt.Errorf("failed to get respondent id: %w", err)
+			return nil, fmt.Errorf("failed to insert respondent: %w", err)
 		}
 	} else if err != nil {
-		return fmt.Errorf("failed to check existing respondent: %w", err)
+		return nil, fmt.Errorf("failed to check existing respondent: %w", err)
+	} else if editToken == "" {
+		return nil, ErrRespondentExists
+	} else if !tokenMatches(editToken, storedHash.String) {
+		return nil, ErrInvalidEditToken
 	}
 
 	// Delete existing responses for this respondent
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		DELETE FROM responses WHERE respondent_id = ?
 	`, respondentID)
 	if err != nil {
-		return fmt.Errorf("failed to delete existing responses: %w", err)
+		return nil, fmt.Errorf("failed to delete existing responses: %w", err)
 	}
 
 	// Insert new responses
 	for _, response := range req.Responses {
-		_, err = tx.Exec(`
-			INSERT INTO responses (respondent_id, event_date_id, available)
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO responses (respondent_id, event_date_id, availability)
 			VALUES (?, ?, ?)
-		`, respondentID, response.EventDateID, response.Available)
+		`, respondentID, response.EventDateID, response.ResolvedAvailability())
 
 		if err != nil {
-			return fmt.Errorf("failed to insert response: %w", err)
+			return nil, fmt.Errorf("failed to insert response: %w", err)
 		}
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &models.SubmitResponseResult{
+		RespondentID: int(respondentID),
+		EditToken:    newEditToken,
+	}, nil
+}
+
+// verifyResponseDates checks that the event exists and that every response
+// refers to one of its date options, at most once
+func verifyResponseDates(ctx context.Context, tx *sqlTx, eventID string, responses []models.ResponseRequest) error {
+	var id string
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM events WHERE id = ?
+	`, eventID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrEventNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify event: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM event_dates WHERE event_id = ?
+	`, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event dates: %w", err)
+	}
+	defer rows.Close()
+
+	dateIDs := make(map[int]bool)
+	for rows.Next() {
+		var dateID int
+		if err := rows.Scan(&dateID); err != nil {
+			return fmt.Errorf("failed to scan event date: %w", err)
+		}
+		dateIDs[dateID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get event dates: %w", err)
+	}
+
+	answered := make(map[int]bool)
+	for _, response := range responses {
+		if !dateIDs[response.EventDateID] {
+			return &DateNotInEventError{EventDateID: response.EventDateID}
+		}
+		if answered[response.EventDateID] {
+			return &DuplicateResponseError{EventDateID: response.EventDateID}
+		}
+		answered[response.EventDateID] = true
 	}
 
 	return nil
 }
 
 // GetEventResults gets aggregated results for an event
-func GetEventResults(db *sql.DB, eventID string) (*models.EventResults, error) {
+func (s *SQLStore) GetEventResults(ctx context.Context, eventID string) (*models.EventResults, error) {
 	// Get event
-	event, err := GetEvent(db, eventID)
+	event, err := s.GetEvent(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get respondents with their responses
-	respondents, err := getRespondents(db, eventID)
+	respondents, err := s.getRespondents(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate summary statistics
-	summary := make(map[int]models.AvailabilitySummary)
-
-	for _, date := range event.Dates {
-		availableNames := []string{}
-		availableCount := 0
-		unavailableCount := 0
-
-		for _, respondent := range respondents {
-			for _, response := range respondent.Responses {
-				if response.EventDateID == date.ID {
-					if response.Available {
-						availableCount++
-						availableNames = append(availableNames, respondent.Name)
-					} else {
-						unavailableCount++
-					}
-					break
-				}
-			}
-		}
-
-		summary[date.ID] = models.AvailabilitySummary{
-			EventDateID:      date.ID,
-			AvailableCount:   availableCount,
-			UnavailableCount: unavailableCount,
-			AvailableNames:   availableNames,
-		}
-	}
-
-	return &models.EventResults{
-		Event:       *event,
-		Respondents: respondents,
-		Summary:     summary,
-	}, nil
+	return buildEventResults(event, respondents), nil
 }
 
 // getRespondents gets all respondents for an event with their responses
-func getRespondents(db *sql.DB, eventID string) ([]models.Respondent, error) {
-	rows, err := db.Query(`
+func (s *SQLStore) getRespondents(ctx context.Context, eventID string) ([]models.Respondent, error) {
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT id, event_id, name, created_at
 		FROM respondents WHERE event_id = ?
 		ORDER BY created_at
@@ -275,8 +350,8 @@ func getRespondents(db *sql.DB, eventID string) ([]models.Respondent, error) {
 		}
 
 		// Get responses for this respondent
-		responseRows, err := db.Query(`
-			SELECT id, respondent_id, event_date_id, available
+		responseRows, err := s.db.QueryContext(ctx, `
+			SELECT id, respondent_id, event_date_id, availability
 			FROM responses WHERE respondent_id = ?
 		`, respondent.ID)
 		if err != nil {
@@ -286,11 +361,12 @@ func getRespondents(db *sql.DB, eventID string) ([]models.Respondent, error) {
 		var responses []models.Response
 		for responseRows.Next() {
 			var response models.Response
-			err := responseRows.Scan(&response.ID, &response.RespondentID, &response.EventDateID, &response.Available)
+			err := responseRows.Scan(&response.ID, &response.RespondentID, &response.EventDateID, &response.Availability)
 			if err != nil {
 				responseRows.Close()
 				return nil, fmt.Errorf("failed to scan response: %w", err)
 			}
+			response.Available = response.Availability == models.AvailabilityYes
 			responses = append(responses, response)
 		}
 		responseRows.Close()
@@ -303,10 +379,36 @@ func getRespondents(db *sql.DB, eventID string) ([]models.Respondent, error) {
 }
 
 // FinalizeEvent sets the finalized date for an event
-func FinalizeEvent(db *sql.DB, eventID string, eventDateID int) error {
+func (s *SQLStore) FinalizeEvent(ctx context.Context, eventID string, eventDateID int) error {
+	// Start transaction
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Mark the event as changed first: this locks the event row, so a
+	// concurrent RemoveEventDate, which updates the row before deleting the
+	// date, cannot remove the date between the check and the update
+	if err := bumpSequence(ctx, tx, eventID); err != nil {
+		return err
+	}
+
+	// A decision stands until its date option is removed
+	var finalized sql.NullInt64
+	err = tx.QueryRowContext(ctx, `
+		SELECT finalized_date_id FROM events WHERE id = ?
+	`, eventID).Scan(&finalized)
+	if err != nil {
+		return fmt.Errorf("failed to read finalized date: %w", err)
+	}
+	if finalized.Valid {
+		return ErrEventFinalized
+	}
+
 	// Verify event date belongs to the event
 	var count int
-	err := db.QueryRow(`
+	err = tx.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM event_dates
 		WHERE id = ? AND event_id = ?
 	`, eventDateID, eventID).Scan(&count)
@@ -316,17 +418,289 @@ func FinalizeEvent(db *sql.DB, eventID string, eventDateID int) error {
 	}
 
 	if count == 0 {
-		return fmt.Errorf("event date does not belong to this event")
+		return &DateNotInEventError{EventDateID: eventDateID}
 	}
 
 	// Update event with finalized date
-	_, err = db.Exec(`
-		UPDATE events SET finalized_date_id = ? WHERE id = ?
-	`, eventDateID, eventID)
+	_, err = tx.ExecContext(ctx, `
+		UPDATE events
+		SET finalized_date_id = ?, finalized_at = ?
+		WHERE id = ?
+	`, eventDateID, time.Now(), eventID)
 
 	if err != nil {
 		return fmt.Errorf("failed to finalize event: %w", err)
 	}
 
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateEventName renames an event
+func (s *SQLStore) UpdateEventName(ctx context.Context, eventID string, name string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE events SET name = ?, sequence = sequence + 1, updated_at = ? WHERE id = ?
+	`, name, time.Now(), eventID)
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
+
+// AddEventDate adds a new date option to an existing event
+func (s *SQLStore) AddEventDate(ctx context.Context, eventID string, req models.CreateDateRequest) (*models.EventDate, error) {
+	// Start transaction
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Mark the event as changed, which also verifies it exists
+	if err := bumpSequence(ctx, tx, eventID); err != nil {
+		return nil, err
+	}
+
+	var timeZone string
+	err = tx.QueryRowContext(ctx, `
+		SELECT time_zone FROM events WHERE id = ?
+	`, eventID).Scan(&timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event time zone: %w", err)
+	}
+
+	var dateID int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO event_dates (event_id, date, start_time, end_time)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`, eventID, req.Date, req.StartTime, req.EndTime).Scan(&dateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert event date: %w", err)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	event := models.Event{
+		ID:       eventID,
+		TimeZone: timeZone,
+		Dates: []models.EventDate{{
+			ID:        dateID,
+			EventID:   eventID,
+			Date:      req.Date,
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+		}},
+	}
+	if err := localizeInEventZone(&event); err != nil {
+		return nil, err
+	}
+
+	return &event.Dates[0], nil
+}
+
+// RemoveEventDate removes a date option from an event together with the
+// responses pointing at it. If the event was finalized on that date, the
+// event goes back to being open.
+func (s *SQLStore) RemoveEventDate(ctx context.Context, eventID string, eventDateID int) error {
+	// Start transaction
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Verify event date belongs to the event
+	var count int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM event_dates
+		WHERE id = ? AND event_id = ?
+	`, eventDateID, eventID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to verify event date: %w", err)
+	}
+	if count == 0 {
+		return dateNotFound(ctx, tx, eventID)
+	}
+
+	// Keep at least one option
+	var total int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM event_dates WHERE event_id = ?
+	`, eventID).Scan(&total)
+	if err != nil {
+		return fmt.Errorf("failed to count event dates: %w", err)
+	}
+	if total <= 1 {
+		return ErrLastEventDate
+	}
+
+	// Delete responses for the date
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM responses WHERE event_date_id = ?
+	`, eventDateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete responses: %w", err)
+	}
+
+	// Clear finalized date if it pointed at this option
+	_, err = tx.ExecContext(ctx, `
+		UPDATE events SET finalized_date_id = NULL, finalized_at = NULL
+		WHERE id = ? AND finalized_date_id = ?
+	`, eventID, eventDateID)
+	if err != nil {
+		return fmt.Errorf("failed to clear finalized date: %w", err)
+	}
+
+	// Mark the event as changed
+	if err := bumpSequence(ctx, tx, eventID); err != nil {
+		return err
+	}
+
+	// Delete the date itself
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM event_dates WHERE id = ?
+	`, eventDateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete event date: %w", err)
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// dateNotFound tells a missing date option apart from a missing event
+func dateNotFound(ctx context.Context, tx *sqlTx, eventID string) error {
+	var exists int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM events WHERE id = ?
+	`, eventID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to verify event: %w", err)
+	}
+	if exists == 0 {
+		return ErrEventNotFound
+	}
+	return ErrDateNotFound
+}
+
+// DeleteEvent deletes an event together with its dates, respondents and responses
+func (s *SQLStore) DeleteEvent(ctx context.Context, eventID string) error {
+	// Start transaction
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteEventTx(ctx, tx, eventID); err != nil {
+		return err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// deleteEventTx removes an event and everything that references it
+func deleteEventTx(ctx context.Context, tx *sqlTx, eventID string) error {
+	// Verify event exists
+	var exists int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM events WHERE id = ?
+	`, eventID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to verify event: %w", err)
+	}
+	if exists == 0 {
+		return ErrEventNotFound
+	}
+
+	// Delete responses
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM responses WHERE respondent_id IN (
+			SELECT id FROM respondents WHERE event_id = ?
+		)
+	`, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to delete responses: %w", err)
+	}
+
+	// Delete respondents
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM respondents WHERE event_id = ?
+	`, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to delete respondents: %w", err)
+	}
+
+	// Clear finalized date so the event no longer points at its dates
+	_, err = tx.ExecContext(ctx, `
+		UPDATE events SET finalized_date_id = NULL WHERE id = ?
+	`, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to clear finalized date: %w", err)
+	}
+
+	// Delete event dates
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM event_dates WHERE event_id = ?
+	`, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to delete event dates: %w", err)
+	}
+
+	// Delete event
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM events WHERE id = ?
+	`, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	return nil
+}
+
+// bumpSequence records that an event changed so calendar clients pick up the
+// new revision. It returns ErrEventNotFound if the event does not exist.
+func bumpSequence(ctx context.Context, tx *sqlTx, eventID string) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE events SET sequence = sequence + 1, updated_at = ? WHERE id = ?
+	`, time.Now(), eventID)
+	if err != nil {
+		return fmt.Errorf("failed to update event sequence: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package database
+
+import "github.com/jleikdra/finn-en-dato/backend/internal/models"
+
+// buildEventResults aggregates respondents' answers per date option. It is
+// shared by every EventStore implementation so they report identical results.
+func buildEventResults(event *models.Event, respondents []models.Respondent) *models.EventResults {
+	// Calculate summary statistics
+	summary := make(map[int]models.AvailabilitySummary)
+
+	for _, date := range event.Dates {
+		availableNames := []string{}
+		maybeNames := []string{}
+		noAnswerNames := []string{}
+		availableCount := 0
+		maybeCount := 0
+		unavailableCount := 0
+
+		for _, respondent := range respondents {
+			answered := false
+			for _, response := range respondent.Responses {
+				if response.EventDateID == date.ID {
+					answered = true
+					switch response.Availability {
+					case models.AvailabilityYes:
+						availableCount++
+						availableNames = append(availableNames, respondent.Name)
+					case models.AvailabilityMaybe:
+						maybeCount++
+						maybeNames = append(maybeNames, respondent.Name)
+					default:
+						unavailableCount++
+					}
+					break
+				}
+			}
+
+			// Respondents who answered before this date was added
+			if !answered {
+				noAnswerNames = append(noAnswerNames, respondent.Name)
+			}
+		}
+
+		summary[date.ID] = models.AvailabilitySummary{
+			EventDateID:      date.ID,
+			AvailableCount:   availableCount,
+			UnavailableCount: unavailableCount,
+			AvailableNames:   availableNames,
+			MaybeCount:       maybeCount,
+			MaybeNames:       maybeNames,
+			NoAnswerCount:    len(noAnswerNames),
+			NoAnswerNames:    noAnswerNames,
+		}
+	}
+
+	return &models.EventResults{
+		Event:       *event,
+		Respondents: respondents,
+		Summary:     summary,
+	}
+}
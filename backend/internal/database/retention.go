@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetentionPolicy decides when old events are purged. A zero duration
+// disables that rule; an event is purged as soon as any enabled rule matches.
+type RetentionPolicy struct {
+	// AfterLastDate purges events this long after their last date option
+	AfterLastDate time.Duration
+
+	// AfterFinalized purges events this long after they were finalized
+	AfterFinalized time.Duration
+}
+
+// Enabled reports whether any retention rule is active
+func (p RetentionPolicy) Enabled() bool {
+	return p.AfterLastDate > 0 || p.AfterFinalized > 0
+}
+
+// PurgeExpiredEvents deletes every event that has expired under the policy
+// as of now and returns how many were removed. Each event is deleted in its
+// own transaction so the purge never holds the database for long.
+func (s *SQLStore) PurgeExpiredEvents(ctx context.Context, policy RetentionPolicy, now time.Time) (int, error) {
+	eventIDs, err := s.expiredEventIDs(ctx, policy, now)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, eventID := range eventIDs {
+		err := s.DeleteEvent(ctx, eventID)
+		if errors.Is(err, ErrEventNotFound) {
+			// Already deleted by the organizer in the meantime
+			continue
+		}
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge event %s: %w", eventID, err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// expiredEventIDs lists the events that have expired under the policy
+func (s *SQLStore) expiredEventIDs(ctx context.Context, policy RetentionPolicy, now time.Time) ([]string, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	// Only the enabled rules become conditions. Dates are compared as
+	// YYYY-MM-DD text, so comparing strings compares days.
+	var conditions []string
+	var args []any
+	if policy.AfterLastDate > 0 {
+		conditions = append(conditions, `(
+			SELECT MAX(`+s.dialect.dateText("d.date")+`) FROM event_dates d WHERE d.event_id = e.id
+		) < ?`)
+		args = append(args, now.Add(-policy.AfterLastDate).Format("2006-01-02"))
+	}
+	if policy.AfterFinalized > 0 {
+		conditions = append(conditions, s.dialect.timestampBefore("e.finalized_at"))
+		args = append(args, now.Add(-policy.AfterFinalized).UTC())
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.id FROM events e
+		WHERE `+strings.Join(conditions, " OR "), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired events: %w", err)
+	}
+	defer rows.Close()
+
+	var eventIDs []string
+	for rows.Next() {
+		var eventID string
+		if err := rows.Scan(&eventID); err != nil {
+			return nil, fmt.Errorf("failed to scan expired event: %w", err)
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+
+	return eventIDs, rows.Err()
+}
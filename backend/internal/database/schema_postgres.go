@@ -0,0 +1,59 @@
+package database
+
+// postgresMigrations lists every PostgreSQL schema change in order, with the
+// same rules as sqliteMigrations. PostgreSQL support started from the schema
+// SQLite had reached by then, so its history begins there.
+var postgresMigrations = []migration{
+	{
+		version: 1,
+		name:    "initial schema",
+		up: execSQL(`
+		CREATE TABLE events (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			time_zone TEXT NOT NULL DEFAULT 'Europe/Oslo',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			updated_at TIMESTAMPTZ,
+			sequence INTEGER NOT NULL DEFAULT 0,
+			admin_token_hash TEXT,
+			finalized_date_id INTEGER,
+			finalized_at TIMESTAMPTZ
+		);
+
+		CREATE TABLE event_dates (
+			id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			event_id TEXT NOT NULL REFERENCES events(id),
+			date DATE NOT NULL,
+			start_time TIME NOT NULL,
+			end_time TIME NOT NULL
+		);
+		CREATE INDEX event_dates_event_id_idx ON event_dates (event_id);
+
+		-- events and event_dates point at each other
+		ALTER TABLE events ADD FOREIGN KEY (finalized_date_id) REFERENCES event_dates(id);
+
+		CREATE TABLE respondents (
+			id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			event_id TEXT NOT NULL REFERENCES events(id),
+			name TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			edit_token_hash TEXT
+		);
+		CREATE INDEX respondents_event_id_idx ON respondents (event_id);
+
+		CREATE TABLE responses (
+			id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			respondent_id INTEGER NOT NULL REFERENCES respondents(id),
+			event_date_id INTEGER NOT NULL REFERENCES event_dates(id),
+			availability TEXT NOT NULL CHECK (availability IN ('yes', 'maybe', 'no')),
+			UNIQUE (respondent_id, event_date_id)
+		);
+		CREATE INDEX responses_event_date_id_idx ON responses (event_date_id);
+		`),
+	},
+	{
+		version: 2,
+		name:    "unique respondent names",
+		up:      execSQL(uniqueRespondentNames),
+	},
+}
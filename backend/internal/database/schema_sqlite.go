@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// sqliteMigrations lists every SQLite schema change in order. Versions must
+// be consecutive starting at 1; never edit a migration once it has shipped,
+// add a new one instead.
+var sqliteMigrations = []migration{
+	{
+		version: 1,
+		name:    "initial schema",
+		up: execSQL(`
+		-- Table 1: events
+		CREATE TABLE IF NOT EXISTS events (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			finalized_date_id INTEGER,
+			FOREIGN KEY (finalized_date_id) REFERENCES event_dates(id)
+		);
+
+		-- Table 2: event_dates
+		CREATE TABLE IF NOT EXISTS event_dates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_id TEXT NOT NULL,
+			date DATE NOT NULL,
+			start_time TIME NOT NULL,
+			end_time TIME NOT NULL,
+			FOREIGN KEY (event_id) REFERENCES events(id)
+		);
+
+		-- Table 3: respondents
+		CREATE TABLE IF NOT EXISTS respondents (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (event_id) REFERENCES events(id)
+		);
+
+		-- Table 4: responses
+		CREATE TABLE IF NOT EXISTS responses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			respondent_id INTEGER NOT NULL,
+			event_date_id INTEGER NOT NULL,
+			available BOOLEAN NOT NULL,
+			UNIQUE(respondent_id, event_date_id),
+			FOREIGN KEY (respondent_id) REFERENCES respondents(id),
+			FOREIGN KEY (event_date_id) REFERENCES event_dates(id)
+		);
+		`),
+	},
+	{
+		version: 2,
+		name:    "organizer admin tokens",
+		up:      addColumn("events", "admin_token_hash", "TEXT"),
+	},
+	{
+		version: 3,
+		name:    "respondent edit tokens",
+		up:      addColumn("respondents", "edit_token_hash", "TEXT"),
+	},
+	{
+		version: 4,
+		name:    "three-state availability",
+		up: execSQL(`
+		ALTER TABLE responses ADD COLUMN availability TEXT NOT NULL DEFAULT 'no'
+			CHECK (availability IN ('yes', 'maybe', 'no'));
+		UPDATE responses SET availability = CASE WHEN available THEN 'yes' ELSE 'no' END;
+		ALTER TABLE responses DROP COLUMN available;
+		`),
+	},
+	{
+		version: 5,
+		name:    "event finalization timestamp",
+		up:      addColumn("events", "finalized_at", "TIMESTAMP"),
+	},
+	{
+		version: 6,
+		name:    "event revision tracking",
+		up: execSQL(`
+		ALTER TABLE events ADD COLUMN sequence INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE events ADD COLUMN updated_at TIMESTAMP;
+		`),
+	},
+	{
+		version: 7,
+		name:    "event time zones",
+		up:      addColumn("events", "time_zone", "TEXT NOT NULL DEFAULT 'Europe/Oslo'"),
+	},
+	{
+		version: 8,
+		name:    "unique respondent names",
+		up:      execSQL(uniqueRespondentNames),
+	},
+}
+
+// addColumn returns a migration step that adds a column to an existing table.
+// Databases from before the migration subsystem may already have the column,
+// so it is left alone if present.
+func addColumn(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		exists, err := columnExists(tx, table, column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+		if err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+		}
+		return nil
+	}
+}
+
+// columnExists reports whether a table has a column with the given name
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			found = true
+		}
+	}
+
+	return found, rows.Err()
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// EventStore persists events, their date options and respondents' answers.
+// Implementations report failures with the errors in errors.go so callers can
+// handle every backend the same way. The context carries the request's
+// deadline and request ID.
+type EventStore interface {
+	// CreateEvent creates an event and returns it with its one-time admin token
+	CreateEvent(ctx context.Context, req models.CreateEventRequest) (*models.CreateEventResponse, error)
+
+	// GetEvent retrieves an event with its date options
+	GetEvent(ctx context.Context, eventID string) (*models.Event, error)
+
+	// UpdateEventName renames an event
+	UpdateEventName(ctx context.Context, eventID string, name string) error
+
+	// AddEventDate adds a date option to an event
+	AddEventDate(ctx context.Context, eventID string, req models.CreateDateRequest) (*models.EventDate, error)
+
+	// RemoveEventDate removes a date option and the responses pointing at it
+	RemoveEventDate(ctx context.Context, eventID string, eventDateID int) error
+
+	// DeleteEvent deletes an event and everything belonging to it
+	DeleteEvent(ctx context.Context, eventID string) error
+
+	// FinalizeEvent picks the date option the event will happen on
+	FinalizeEvent(ctx context.Context, eventID string, eventDateID int) error
+
+	// VerifyAdminToken checks the organizer token for an event
+	VerifyAdminToken(ctx context.Context, eventID string, token string) error
+
+	// SubmitResponse records or, given the right edit token, replaces a respondent's answers
+	SubmitResponse(ctx context.Context, eventID string, req models.SubmitResponseRequest, editToken string) (*models.SubmitResponseResult, error)
+
+	// GetEventResults gets an event with its respondents and per-date summary
+	GetEventResults(ctx context.Context, eventID string) (*models.EventResults, error)
+
+	// PurgeExpiredEvents deletes events that have expired under the policy
+	PurgeExpiredEvents(ctx context.Context, policy RetentionPolicy, now time.Time) (int, error)
+}
+
+// SQLStore is an EventStore backed by a SQLite or PostgreSQL database
+type SQLStore struct {
+	db      *sqlDB
+	dialect *dialect
+}
+
+// NewSQLiteStore creates a store on an open SQLite database
+func NewSQLiteStore(db *sql.DB) *SQLStore {
+	return newSQLStore(db, sqliteDialect)
+}
+
+// NewPostgresStore creates a store on an open PostgreSQL database
+func NewPostgresStore(db *sql.DB) *SQLStore {
+	return newSQLStore(db, postgresDialect)
+}
+
+func newSQLStore(db *sql.DB, d *dialect) *SQLStore {
+	return &SQLStore{db: &sqlDB{raw: db, dialect: d}, dialect: d}
+}
+
+// Close closes the underlying database
+func (s *SQLStore) Close() error {
+	return s.db.raw.Close()
+}
+
+// Ready checks that the database answers and is migrated to the schema
+// version this build expects
+func (s *SQLStore) Ready(ctx context.Context) error {
+	if err := s.db.raw.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	var version sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if latest := s.LatestSchemaVersion(); int(version.Int64) != latest {
+		return fmt.Errorf("database is at schema version %d, expected %d", version.Int64, latest)
+	}
+
+	return nil
+}
+
+var (
+	_ EventStore = (*SQLStore)(nil)
+	_ EventStore = (*MemoryStore)(nil)
+	_ EventStore = (*instrumentedStore)(nil)
+)
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// newToken generates a random secret token and its hash for storage
+func newToken() (token string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+// hashToken returns the hex encoded SHA-256 hash of a token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenMatches compares a presented token against a stored hash in constant time
+func tokenMatches(token string, storedHash string) bool {
+	if token == "" || storedHash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(storedHash)) == 1
+}
+
+// VerifyAdminToken checks that token is the organizer token for an event
+func (s *SQLStore) VerifyAdminToken(ctx context.Context, eventID string, token string) error {
+	var storedHash sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT admin_token_hash FROM events WHERE id = ?
+	`, eventID).Scan(&storedHash)
+	if err == sql.ErrNoRows {
+		return ErrEventNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get admin token: %w", err)
+	}
+
+	if !tokenMatches(token, storedHash.String) {
+		return ErrInvalidAdminToken
+	}
+
+	return nil
+}
+
+// ClaimAdminToken gives an event created before admin tokens existed a new
+// token and returns it. It fails with ErrAdminTokenSet if the event already
+// has one, so each legacy event can be claimed only once.
+func (s *SQLStore) ClaimAdminToken(ctx context.Context, eventID string) (string, error) {
+	token, hash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE events SET admin_token_hash = ? WHERE id = ? AND admin_token_hash IS NULL
+	`, hash, eventID)
+	if err != nil {
+		return "", fmt.Errorf("failed to set admin token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("failed to set admin token: %w", err)
+	}
+	if rows == 1 {
+		return token, nil
+	}
+
+	// Nothing changed: the event is missing or already has a token
+	var id string
+	err = s.db.QueryRowContext(ctx, `SELECT id FROM events WHERE id = ?`, eventID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", ErrEventNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get event: %w", err)
+	}
+	return "", ErrAdminTokenSet
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// newSQLiteStore opens a migrated SQLite store in a temporary directory
+func newSQLiteStore(t *testing.T) *SQLStore {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestClaimAdminToken(t *testing.T) {
+	ctx := context.Background()
+	store := newSQLiteStore(t)
+
+	// An event from before admin tokens has a NULL hash
+	_, err := store.db.ExecContext(ctx, `
+		INSERT INTO events (id, name, created_at) VALUES ('legacy', 'Old event', CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.VerifyAdminToken(ctx, "legacy", "anything"); !errors.Is(err, ErrInvalidAdminToken) {
+		t.Fatalf("VerifyAdminToken before claim = %v, want ErrInvalidAdminToken", err)
+	}
+
+	token, err := store.ClaimAdminToken(ctx, "legacy")
+	if err != nil {
+		t.Fatalf("ClaimAdminToken: %v", err)
+	}
+	if err := store.VerifyAdminToken(ctx, "legacy", token); err != nil {
+		t.Errorf("VerifyAdminToken with claimed token = %v", err)
+	}
+
+	if _, err := store.ClaimAdminToken(ctx, "legacy"); !errors.Is(err, ErrAdminTokenSet) {
+		t.Errorf("second ClaimAdminToken = %v, want ErrAdminTokenSet", err)
+	} else if isFault(err) {
+		t.Errorf("second ClaimAdminToken = %v, counted as a fault", err)
+	}
+	if _, err := store.ClaimAdminToken(ctx, "missing"); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("ClaimAdminToken on missing event = %v, want ErrEventNotFound", err)
+	}
+}
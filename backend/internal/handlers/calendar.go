@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/ical"
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// calendarProdID identifies this application in exported calendars
+const calendarProdID = "-//finn-en-dato//finn-en-dato//NO"
+
+// getEventICS handles GET /api/v1/events/{id}/event.ics
+func (h *EventHandler) getEventICS(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	event, err := h.store.GetEvent(r.Context(), eventID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	date := finalizedDate(event)
+	if date == nil {
+		writeError(w, r, errEventNotFinalized)
+		return
+	}
+
+	vevent, err := calendarEvent(event, *date)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	vevent.UID = optionUID(event.ID, date.ID)
+	vevent.Status = ical.StatusConfirmed
+
+	cal := ical.Calendar{
+		ProdID: calendarProdID,
+		Method: "PUBLISH",
+		Events: []ical.Event{vevent},
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="event.ics"`)
+	cal.WriteTo(w)
+}
+
+// getOptionsICS handles GET /api/v1/events/{id}/options.ics. Before the event is
+// finalized every date option is a tentative event; afterwards the chosen
+// option is confirmed and the others are cancelled.
+func (h *EventHandler) getOptionsICS(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	event, err := h.store.GetEvent(r.Context(), eventID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	cal := ical.Calendar{
+		ProdID: calendarProdID,
+		Method: "PUBLISH",
+		Name:   event.Name,
+	}
+
+	for _, date := range event.Dates {
+		vevent, err := calendarEvent(event, date)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		vevent.UID = optionUID(event.ID, date.ID)
+
+		switch {
+		case event.FinalizedDateID == nil:
+			vevent.Status = ical.StatusTentative
+		case *event.FinalizedDateID == date.ID:
+			vevent.Status = ical.StatusConfirmed
+		default:
+			vevent.Status = ical.StatusCancelled
+		}
+
+		cal.Events = append(cal.Events, vevent)
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	cal.WriteTo(w)
+}
+
+// finalizedDate returns the date option an event was finalized on, if any
+func finalizedDate(event *models.Event) *models.EventDate {
+	if event.FinalizedDateID == nil {
+		return nil
+	}
+	for i := range event.Dates {
+		if event.Dates[i].ID == *event.FinalizedDateID {
+			return &event.Dates[i]
+		}
+	}
+	return nil
+}
+
+// calendarEvent builds a VEVENT for one date option of an event. Times are
+// written in UTC so calendar clients need no time zone definitions.
+func calendarEvent(event *models.Event, date models.EventDate) (ical.Event, error) {
+	loc, err := event.Location()
+	if err != nil {
+		return ical.Event{}, fmt.Errorf("invalid event time zone %q: %w", event.TimeZone, err)
+	}
+
+	start, end, err := date.Range(loc)
+	if err != nil {
+		return ical.Event{}, err
+	}
+
+	stamp := event.CreatedAt
+	if event.UpdatedAt != nil {
+		stamp = *event.UpdatedAt
+	}
+
+	return ical.Event{
+		Sequence: event.Sequence,
+		Summary:  event.Name,
+		Stamp:    stamp,
+		Start:    start.UTC(),
+		End:      end.UTC(),
+	}, nil
+}
+
+// optionUID returns the stable calendar UID for one date option of an event.
+// The finalized option keeps its UID in event.ics, so importing either file
+// updates the same calendar entry.
+func optionUID(eventID string, eventDateID int) string {
+	return fmt.Sprintf("%s-%d@finn-en-dato", eventID, eventDateID)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/golden"
+)
+
+// dtstamp matches DTSTAMP lines, which depend on when the event was changed
+var dtstamp = regexp.MustCompile(`(?m)^DTSTAMP:\d{8}T\d{6}Z\r$`)
+
+// normalizeICS replaces the parts of a calendar that differ between runs: the
+// random event ID and the DTSTAMP values
+func normalizeICS(body, eventID string) []byte {
+	body = strings.ReplaceAll(body, eventID, "EVENT-ID")
+	return []byte(dtstamp.ReplaceAllString(body, "DTSTAMP:STAMP\r"))
+}
+
+func TestEventICS(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	path := "/api/v1/events/" + created.ID + "/event.ics"
+
+	// Nothing to export until a date is chosen
+	expectError(t, api.do(http.MethodGet, path, nil), http.StatusConflict, "event_not_finalized")
+	expectError(t, api.do(http.MethodGet, "/api/v1/events/missing/event.ics", nil), http.StatusNotFound, "event_not_found")
+
+	api.finalize(created, created.Dates[1].ID)
+
+	rec := api.do(http.MethodGet, path, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if ct := rec.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="event.ics"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	golden.Check(t, "event.ics", normalizeICS(rec.Body.String(), created.ID))
+
+	// The UID stays the same so calendar clients update the entry in place
+	again := api.do(http.MethodGet, path, nil)
+	if again.Body.String() != rec.Body.String() {
+		t.Errorf("second export differs:\n%s\nfirst:\n%s", again.Body, rec.Body)
+	}
+}
+
+func TestOptionsICS(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	path := "/api/v1/events/" + created.ID + "/options.ics"
+
+	expectError(t, api.do(http.MethodGet, "/api/v1/events/missing/options.ics", nil), http.StatusNotFound, "event_not_found")
+
+	before := api.do(http.MethodGet, path, nil)
+	expectStatus(t, before, http.StatusOK)
+	golden.Check(t, "options_tentative.ics", normalizeICS(before.Body.String(), created.ID))
+
+	api.finalize(created, created.Dates[1].ID)
+
+	after := api.do(http.MethodGet, path, nil)
+	expectStatus(t, after, http.StatusOK)
+	golden.Check(t, "options_finalized.ics", normalizeICS(after.Body.String(), created.ID))
+
+	// Every option keeps its UID and moves to a higher SEQUENCE, so clients
+	// replace the tentative entries instead of adding new ones
+	was, now := vevents(before.Body.String()), vevents(after.Body.String())
+	if len(was) != len(created.Dates) || len(now) != len(created.Dates) {
+		t.Fatalf("got %d and %d events, want %d", len(was), len(now), len(created.Dates))
+	}
+	wantStatus := []string{"CANCELLED", "CONFIRMED", "CANCELLED"}
+	for i := range now {
+		if was[i]["UID"] != now[i]["UID"] {
+			t.Errorf("option %d: UID changed from %q to %q", i, was[i]["UID"], now[i]["UID"])
+		}
+		if was[i]["STATUS"] != "TENTATIVE" {
+			t.Errorf("option %d: STATUS before finalizing = %q, want TENTATIVE", i, was[i]["STATUS"])
+		}
+		if now[i]["STATUS"] != wantStatus[i] {
+			t.Errorf("option %d: STATUS after finalizing = %q, want %s", i, now[i]["STATUS"], wantStatus[i])
+		}
+		if seq(t, now[i]) <= seq(t, was[i]) {
+			t.Errorf("option %d: SEQUENCE went from %s to %s", i, was[i]["SEQUENCE"], now[i]["SEQUENCE"])
+		}
+	}
+
+	// event.ics describes the confirmed option exactly as options.ics does
+	event := api.do(http.MethodGet, "/api/v1/events/"+created.ID+"/event.ics", nil)
+	expectStatus(t, event, http.StatusOK)
+	confirmed := vevents(event.Body.String())
+	if len(confirmed) != 1 {
+		t.Fatalf("event.ics has %d events, want 1", len(confirmed))
+	}
+	for name, value := range now[1] {
+		if confirmed[0][name] != value {
+			t.Errorf("event.ics %s = %q, options.ics has %q", name, confirmed[0][name], value)
+		}
+	}
+}
+
+// seq reads a VEVENT's SEQUENCE
+func seq(t *testing.T, vevent map[string]string) int {
+	t.Helper()
+	n, err := strconv.Atoi(vevent["SEQUENCE"])
+	if err != nil {
+		t.Fatalf("invalid SEQUENCE %q", vevent["SEQUENCE"])
+	}
+	return n
+}
+
+// vevents returns the properties of each VEVENT in a calendar, with folded
+// lines joined
+func vevents(body string) []map[string]string {
+	body = strings.ReplaceAll(body, "\r\n ", "")
+
+	var events []map[string]string
+	var current map[string]string
+	for _, line := range strings.Split(body, "\r\n") {
+		name, value, _ := strings.Cut(line, ":")
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = make(map[string]string)
+		case line == "END:VEVENT":
+			events = append(events, current)
+			current = nil
+		case current != nil:
+			current[name] = value
+		}
+	}
+	return events
+}
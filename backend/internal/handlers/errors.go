@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+	"github.com/jleikdra/finn-en-dato/backend/internal/validation"
+)
+
+// requestError is a client error detected by the handlers themselves
+type requestError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+var (
+	errInvalidJSON        = &requestError{http.StatusBadRequest, "invalid_json", "Invalid JSON"}
+	errBodyTooLarge       = &requestError{http.StatusRequestEntityTooLarge, "body_too_large", "Request body is too large"}
+	errEventDateRequired  = &requestError{http.StatusBadRequest, "event_date_required", "Event date ID is required"}
+	errInvalidEventDateID = &requestError{http.StatusBadRequest, "invalid_event_date_id", "Invalid event date ID"}
+	errUnknownTimeZone    = &requestError{http.StatusBadRequest, "unknown_time_zone", "Unknown time zone"}
+	errAdminTokenRequired = &requestError{http.StatusUnauthorized, "admin_token_required", "Admin token is required"}
+	errEventNotFinalized  = &requestError{http.StatusConflict, "event_not_finalized", "Event is not finalized yet"}
+	errNotFound           = &requestError{http.StatusNotFound, "not_found", "Invalid endpoint"}
+	errMethodNotAllowed   = &requestError{http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed"}
+	errNotReady           = &requestError{http.StatusServiceUnavailable, "not_ready", "Service is not ready"}
+)
+
+// domainErrors maps storage errors to HTTP responses. The error's own text is
+// safe to show to clients and is used as the message.
+var domainErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{database.ErrEventNotFound, http.StatusNotFound, "event_not_found"},
+	{database.ErrDateNotFound, http.StatusNotFound, "event_date_not_found"},
+	{database.ErrInvalidAdminToken, http.StatusForbidden, "invalid_admin_token"},
+	{database.ErrInvalidEditToken, http.StatusForbidden, "invalid_edit_token"},
+	{database.ErrRespondentExists, http.StatusConflict, "respondent_exists"},
+	{database.ErrLastEventDate, http.StatusConflict, "last_event_date"},
+	{database.ErrEventFinalized, http.StatusConflict, "event_finalized"},
+	{database.ErrDateNotInEvent, http.StatusUnprocessableEntity, "date_not_in_event"},
+	{database.ErrDuplicateResponse, http.StatusUnprocessableEntity, "duplicate_response"},
+}
+
+// writeError writes err as a JSON error envelope, choosing the status code
+// from the kind of error. Unknown errors become a 500 without leaking their
+// text and are logged instead.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := errorResponse(err)
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	var limited *rateLimitError
+	if errors.As(err, &limited) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limited.retryAfter.Seconds()))))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// errorResponse picks the status code and body for an error
+func errorResponse(err error) (int, models.ErrorResponse) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.status, models.ErrorResponse{Code: reqErr.code, Message: reqErr.message}
+	}
+
+	var limited *rateLimitError
+	if errors.As(err, &limited) {
+		return http.StatusTooManyRequests, models.ErrorResponse{Code: "rate_limited", Message: "Too many requests, please try again later"}
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		return http.StatusBadRequest, models.ErrorResponse{
+			Code:    "validation_failed",
+			Message: "The request has invalid fields",
+			Details: fieldErrs,
+		}
+	}
+
+	for _, de := range domainErrors {
+		if errors.Is(err, de.err) {
+			return de.status, models.ErrorResponse{Code: de.code, Message: err.Error(), Details: errorDetails(err)}
+		}
+	}
+
+	return http.StatusInternalServerError, models.ErrorResponse{
+		Code:    "internal_error",
+		Message: "Internal server error",
+	}
+}
+
+// errorDetails extracts structured context from typed domain errors
+func errorDetails(err error) any {
+	var notInEvent *database.DateNotInEventError
+	if errors.As(err, &notInEvent) {
+		return map[string]int{"event_date_id": notInEvent.EventDateID}
+	}
+
+	var duplicate *database.DuplicateResponseError
+	if errors.As(err, &duplicate) {
+		return map[string]int{"event_date_id": duplicate.EventDateID}
+	}
+
+	return nil
+}
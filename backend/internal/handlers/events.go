@@ -1,93 +1,60 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jleikdra/finn-en-dato/backend/internal/models"
 	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+	"github.com/jleikdra/finn-en-dato/backend/internal/validation"
+)
+
+const (
+	// AdminTokenHeader carries the organizer token on organizer-only requests
+	AdminTokenHeader = "X-Admin-Token"
+
+	// EditTokenHeader carries a respondent's edit token when changing their responses
+	EditTokenHeader = "X-Edit-Token"
 )
 
 // EventHandler handles HTTP requests for events
 type EventHandler struct {
-	db *sql.DB
+	store  database.EventStore
+	limits Limits
 }
 
 // NewEventHandler creates a new event handler
-func NewEventHandler(db *sql.DB) *EventHandler {
-	return &EventHandler{db: db}
-}
-
-// HandleEvents handles requests to /api/events
-func (h *EventHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodPost:
-		h.createEvent(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+func NewEventHandler(store database.EventStore, limits Limits) *EventHandler {
+	return &EventHandler{store: store, limits: limits}
 }
 
-// HandleEventsByID handles requests to /api/events/{id}
-func (h *EventHandler) HandleEventsByID(w http.ResponseWriter, r *http.Request) {
-	// Extract event ID from URL path
-	path := strings.TrimPrefix(r.URL.Path, "/api/events/")
-	parts := strings.Split(path, "/")
-	eventID := parts[0]
-
-	if eventID == "" {
-		http.Error(w, "Event ID is required", http.StatusBadRequest)
+// createEvent handles POST /api/v1/events
+func (h *EventHandler) createEvent(w http.ResponseWriter, r *http.Request) {
+	if err := h.checkClientRate(r); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	switch r.Method {
-	case http.MethodGet:
-		if len(parts) > 1 && parts[1] == "results" {
-			h.getEventResults(w, r, eventID)
-		} else {
-			h.getEvent(w, r, eventID)
-		}
-	case http.MethodPost:
-		if len(parts) > 1 && parts[1] == "respond" {
-			h.submitResponse(w, r, eventID)
-		} else {
-			http.Error(w, "Invalid endpoint", http.StatusNotFound)
-		}
-	case http.MethodPatch:
-		if len(parts) > 1 && parts[1] == "finalize" {
-			h.finalizeEvent(w, r, eventID)
-		} else {
-			http.Error(w, "Invalid endpoint", http.StatusNotFound)
-		}
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-// createEvent handles POST /api/events
-func (h *EventHandler) createEvent(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateEventRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	// Validate request
-	if req.Name == "" {
-		http.Error(w, "Event name is required", http.StatusBadRequest)
-		return
-	}
-	if len(req.Dates) == 0 {
-		http.Error(w, "At least one date is required", http.StatusBadRequest)
+	if errs := validation.CreateEvent(req, time.Now()); errs != nil {
+		writeError(w, r, errs)
 		return
 	}
+	req.Name = strings.TrimSpace(req.Name)
 
 	// Create event in database
-	event, err := database.CreateEvent(h.db, req)
+	event, err := h.store.CreateEvent(r.Context(), req)
 	if err != nil {
-		http.Error(w, "Failed to create event: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -96,89 +63,262 @@ func (h *EventHandler) createEvent(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(event)
 }
 
-// getEvent handles GET /api/events/{id}
-func (h *EventHandler) getEvent(w http.ResponseWriter, r *http.Request, eventID string) {
-	event, err := database.GetEvent(h.db, eventID)
-	if err == sql.ErrNoRows {
-		http.Error(w, "Event not found", http.StatusNotFound)
+// getEvent handles GET /api/v1/events/{id}. The optional tz query parameter
+// converts the date options to the viewer's time zone.
+func (h *EventHandler) getEvent(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	loc, err := viewerLocation(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
+
+	event, err := h.store.GetEvent(r.Context(), eventID)
 	if err != nil {
-		http.Error(w, "Failed to get event: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	if loc != nil {
+		if err := event.Localize(loc); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(event)
 }
 
-// submitResponse handles POST /api/events/{id}/respond
-func (h *EventHandler) submitResponse(w http.ResponseWriter, r *http.Request, eventID string) {
-	var req models.SubmitResponseRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+// submitResponse handles POST /api/v1/events/{id}/respond
+func (h *EventHandler) submitResponse(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	if err := h.checkClientRate(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := h.checkEventRate(eventID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	// Validate request
-	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+	var req models.SubmitResponseRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
-	if len(req.Responses) == 0 {
-		http.Error(w, "At least one response is required", http.StatusBadRequest)
+
+	// Validate request
+	if errs := validation.SubmitResponse(req); errs != nil {
+		writeError(w, r, errs)
 		return
 	}
 
 	// Submit response in database
-	err := database.SubmitResponse(h.db, eventID, req)
+	result, err := h.store.SubmitResponse(r.Context(), eventID, req, r.Header.Get(EditTokenHeader))
 	if err != nil {
-		http.Error(w, "Failed to submit response: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	result.Message = "Response submitted successfully"
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Response submitted successfully"})
+	json.NewEncoder(w).Encode(result)
 }
 
-// getEventResults handles GET /api/events/{id}/results
-func (h *EventHandler) getEventResults(w http.ResponseWriter, r *http.Request, eventID string) {
-	results, err := database.GetEventResults(h.db, eventID)
-	if err == sql.ErrNoRows {
-		http.Error(w, "Event not found", http.StatusNotFound)
+// getEventResults handles GET /api/v1/events/{id}/results. Like getEvent it
+// accepts a tz query parameter.
+func (h *EventHandler) getEventResults(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	loc, err := viewerLocation(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
+
+	results, err := h.store.GetEventResults(r.Context(), eventID)
 	if err != nil {
-		http.Error(w, "Failed to get event results: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	if loc != nil {
+		if err := results.Event.Localize(loc); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(results)
 }
 
-// finalizeEvent handles PATCH /api/events/{id}/finalize
-func (h *EventHandler) finalizeEvent(w http.ResponseWriter, r *http.Request, eventID string) {
-	var req struct {
-		EventDateID int `json:"event_date_id"`
+// finalizeEvent handles PATCH /api/v1/events/{id}/finalize
+func (h *EventHandler) finalizeEvent(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	if err := h.authorizeAdmin(r, eventID); err != nil {
+		writeError(w, r, err)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+
+	var req models.FinalizeEventRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	if req.EventDateID == 0 {
-		http.Error(w, "Event date ID is required", http.StatusBadRequest)
+		writeError(w, r, errEventDateRequired)
 		return
 	}
 
-	err := database.FinalizeEvent(h.db, eventID, req.EventDateID)
+	err := h.store.FinalizeEvent(r.Context(), eventID, req.EventDateID)
 	if err != nil {
-		http.Error(w, "Failed to finalize event: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "Event finalized successfully"})
-}
\ No newline at end of file
+}
+
+// updateEvent handles PATCH /api/v1/events/{id}
+func (h *EventHandler) updateEvent(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	if err := h.authorizeAdmin(r, eventID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req models.UpdateEventRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if errs := validation.UpdateEvent(req); errs != nil {
+		writeError(w, r, errs)
+		return
+	}
+
+	err := h.store.UpdateEventName(r.Context(), eventID, strings.TrimSpace(req.Name))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.getEvent(w, r)
+}
+
+// deleteEvent handles DELETE /api/v1/events/{id}
+func (h *EventHandler) deleteEvent(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	if err := h.authorizeAdmin(r, eventID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err := h.store.DeleteEvent(r.Context(), eventID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// addEventDate handles POST /api/v1/events/{id}/dates
+func (h *EventHandler) addEventDate(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+
+	if err := h.authorizeAdmin(r, eventID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req models.CreateDateRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	event, err := h.store.GetEvent(r.Context(), eventID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if errs := validation.AddDate(req, event, time.Now()); errs != nil {
+		writeError(w, r, errs)
+		return
+	}
+
+	date, err := h.store.AddEventDate(r.Context(), eventID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(date)
+}
+
+// removeEventDate handles DELETE /api/v1/events/{id}/dates/{dateID}
+func (h *EventHandler) removeEventDate(w http.ResponseWriter, r *http.Request) {
+	eventID := r.PathValue("id")
+	rawDateID := r.PathValue("dateID")
+
+	eventDateID, err := strconv.Atoi(rawDateID)
+	if err != nil {
+		writeError(w, r, errInvalidEventDateID)
+		return
+	}
+
+	if err := h.authorizeAdmin(r, eventID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = h.store.RemoveEventDate(r.Context(), eventID, eventDateID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// viewerLocation loads the time zone from the tz query parameter. It returns
+// nil if none was given.
+func viewerLocation(r *http.Request) (*time.Location, error) {
+	name := r.URL.Query().Get("tz")
+	if name == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errUnknownTimeZone
+	}
+
+	return loc, nil
+}
+
+// authorizeAdmin verifies the organizer token presented for an event
+func (h *EventHandler) authorizeAdmin(r *http.Request, eventID string) error {
+	token := r.Header.Get(AdminTokenHeader)
+	if token == "" {
+		return errAdminTokenRequired
+	}
+
+	return h.store.VerifyAdminToken(r.Context(), eventID, token)
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+	"github.com/jleikdra/finn-en-dato/backend/internal/ratelimit"
+)
+
+func TestCreateAndGetEvent(t *testing.T) {
+	api := newTestAPI(t)
+
+	req := sampleEvent()
+	req.Name = "  Julebord  "
+	created := api.createEvent(req)
+	if created.Name != "Julebord" {
+		t.Errorf("name = %q, want it trimmed", created.Name)
+	}
+	if created.AdminToken == "" {
+		t.Error("no admin token returned")
+	}
+
+	rec := api.do(http.MethodGet, "/api/v1/events/"+created.ID, nil)
+	expectStatus(t, rec, http.StatusOK)
+	var event models.Event
+	decode(t, rec, &event)
+	if event.ID != created.ID || len(event.Dates) != 3 {
+		t.Errorf("GetEvent = %q with %d dates", event.ID, len(event.Dates))
+	}
+	if strings.Contains(rec.Body.String(), "admin_token") {
+		t.Error("GetEvent leaks the admin token")
+	}
+
+	expectError(t, api.do(http.MethodGet, "/api/v1/events/missing", nil), http.StatusNotFound, "event_not_found")
+}
+
+func TestCreateEventErrors(t *testing.T) {
+	api := newTestAPI(t)
+
+	rec := api.do(http.MethodPost, "/api/v1/events", models.CreateEventRequest{Name: "No dates"})
+	expectError(t, rec, http.StatusBadRequest, "validation_failed")
+	if !strings.Contains(rec.Body.String(), `"field":"dates"`) {
+		t.Errorf("validation details do not name the field: %s", rec.Body)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader("{not json"))
+	rec = httptest.NewRecorder()
+	api.mux.ServeHTTP(rec, req)
+	expectError(t, rec, http.StatusBadRequest, "invalid_json")
+}
+
+func TestBodyTooLarge(t *testing.T) {
+	api := newLimitedAPI(t, Limits{MaxBodyBytes: 64})
+
+	req := sampleEvent()
+	req.Name = strings.Repeat("x", 100)
+	expectError(t, api.do(http.MethodPost, "/api/v1/events", req), http.StatusRequestEntityTooLarge, "body_too_large")
+}
+
+func TestRateLimits(t *testing.T) {
+	api := newLimitedAPI(t, Limits{PerClient: ratelimit.New(0.001, 2)})
+
+	api.createEvent(sampleEvent())
+	api.createEvent(sampleEvent())
+	rec := api.do(http.MethodPost, "/api/v1/events", sampleEvent())
+	expectError(t, rec, http.StatusTooManyRequests, "rate_limited")
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+
+	// Reading is never limited
+	expectError(t, api.do(http.MethodGet, "/api/v1/events/missing", nil), http.StatusNotFound, "event_not_found")
+}
+
+func TestAdminOnlyRoutes(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	other := api.createEvent(sampleEvent())
+	base := "/api/v1/events/" + created.ID
+	dateID := created.Dates[0].ID
+
+	routes := []struct {
+		method, path string
+		body         any
+		invalid      any // a body that fails validation
+	}{
+		{http.MethodPatch, base, models.UpdateEventRequest{Name: "Renamed"}, models.UpdateEventRequest{}},
+		{http.MethodDelete, base, nil, nil},
+		{http.MethodPatch, base + "/finalize", map[string]int{"event_date_id": dateID}, map[string]int{}},
+		{http.MethodPost, base + "/dates", models.CreateDateRequest{Date: "2035-12-26", StartTime: "12:00", EndTime: "14:00"}, models.CreateDateRequest{}},
+		{http.MethodDelete, fmt.Sprintf("%s/dates/%d", base, dateID), nil, nil},
+	}
+	for _, route := range routes {
+		t.Run(route.method+" "+strings.TrimPrefix(route.path, base), func(t *testing.T) {
+			expectError(t, api.do(route.method, route.path, route.body), http.StatusUnauthorized, "admin_token_required")
+			// The body is not looked at before the caller is known
+			expectError(t, api.do(route.method, route.path, route.invalid), http.StatusUnauthorized, "admin_token_required")
+			expectError(t, api.do(route.method, route.path, route.invalid, AdminTokenHeader, "wrong"), http.StatusForbidden, "invalid_admin_token")
+			expectError(t, api.do(route.method, route.path, route.body, AdminTokenHeader, "wrong"), http.StatusForbidden, "invalid_admin_token")
+			expectError(t, api.do(route.method, route.path, route.body, AdminTokenHeader, other.AdminToken), http.StatusForbidden, "invalid_admin_token")
+
+			missing := strings.Replace(route.path, created.ID, "missing", 1)
+			expectError(t, api.do(route.method, missing, route.body, AdminTokenHeader, created.AdminToken), http.StatusNotFound, "event_not_found")
+		})
+	}
+
+	// Nothing was changed by the rejected requests
+	event := api.do(http.MethodGet, base, nil)
+	expectStatus(t, event, http.StatusOK)
+	var got models.Event
+	decode(t, event, &got)
+	if got.Name != "Julebord" || len(got.Dates) != 3 || got.FinalizedDateID != nil || got.Sequence != 0 {
+		t.Errorf("event changed by rejected requests: %+v", got)
+	}
+}
+
+func TestUpdateAndDeleteEvent(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	base := "/api/v1/events/" + created.ID
+	admin := []string{AdminTokenHeader, created.AdminToken}
+
+	expectError(t, api.do(http.MethodPatch, base, models.UpdateEventRequest{Name: " "}, admin...), http.StatusBadRequest, "validation_failed")
+
+	rec := api.do(http.MethodPatch, base, models.UpdateEventRequest{Name: " Nyttårsfest "}, admin...)
+	expectStatus(t, rec, http.StatusOK)
+	var event models.Event
+	decode(t, rec, &event)
+	if event.Name != "Nyttårsfest" || event.Sequence != 1 {
+		t.Errorf("renamed event = %q at sequence %d", event.Name, event.Sequence)
+	}
+
+	rec = api.do(http.MethodDelete, base, nil, admin...)
+	expectStatus(t, rec, http.StatusNoContent)
+	expectError(t, api.do(http.MethodGet, base, nil), http.StatusNotFound, "event_not_found")
+	expectError(t, api.do(http.MethodDelete, base, nil, admin...), http.StatusNotFound, "event_not_found")
+}
+
+func TestEventDates(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	other := api.createEvent(sampleEvent())
+	base := "/api/v1/events/" + created.ID
+	admin := []string{AdminTokenHeader, created.AdminToken}
+
+	rec := api.do(http.MethodPost, base+"/dates", models.CreateDateRequest{Date: "2035-12-26", StartTime: "12:00", EndTime: "14:00"}, admin...)
+	expectStatus(t, rec, http.StatusCreated)
+	var added models.EventDate
+	decode(t, rec, &added)
+	if added.ID == 0 || added.EventID != created.ID || added.Start == nil {
+		t.Errorf("added date = %+v", added)
+	}
+
+	// The same slot again is rejected
+	rec = api.do(http.MethodPost, base+"/dates", models.CreateDateRequest{Date: "2035-12-26", StartTime: "12:00", EndTime: "14:00"}, admin...)
+	expectError(t, rec, http.StatusBadRequest, "validation_failed")
+
+	expectError(t, api.do(http.MethodDelete, base+"/dates/abc", nil, admin...), http.StatusBadRequest, "invalid_event_date_id")
+	expectError(t, api.do(http.MethodDelete, fmt.Sprintf("%s/dates/%d", base, other.Dates[0].ID), nil, admin...), http.StatusNotFound, "event_date_not_found")
+
+	for _, date := range append(created.Dates[1:], added) {
+		expectStatus(t, api.do(http.MethodDelete, fmt.Sprintf("%s/dates/%d", base, date.ID), nil, admin...), http.StatusNoContent)
+	}
+	last := fmt.Sprintf("%s/dates/%d", base, created.Dates[0].ID)
+	expectError(t, api.do(http.MethodDelete, last, nil, admin...), http.StatusConflict, "last_event_date")
+}
+
+func TestFinalizeEvent(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	other := api.createEvent(sampleEvent())
+	path := "/api/v1/events/" + created.ID + "/finalize"
+	admin := []string{AdminTokenHeader, created.AdminToken}
+
+	expectError(t, api.do(http.MethodPatch, path, map[string]int{}, admin...), http.StatusBadRequest, "event_date_required")
+
+	rec := api.do(http.MethodPatch, path, map[string]int{"event_date_id": other.Dates[0].ID}, admin...)
+	expectError(t, rec, http.StatusUnprocessableEntity, "date_not_in_event")
+	if want := fmt.Sprintf(`"details":{"event_date_id":%d}`, other.Dates[0].ID); !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("body %s does not contain %s", rec.Body, want)
+	}
+
+	api.finalize(created, created.Dates[2].ID)
+	var event models.Event
+	decode(t, api.do(http.MethodGet, "/api/v1/events/"+created.ID, nil), &event)
+	if event.FinalizedDateID == nil || *event.FinalizedDateID != created.Dates[2].ID || event.FinalizedAt == nil {
+		t.Errorf("finalized on %v at %v", event.FinalizedDateID, event.FinalizedAt)
+	}
+
+	rec = api.do(http.MethodPatch, path, map[string]int{"event_date_id": created.Dates[0].ID}, admin...)
+	expectError(t, rec, http.StatusConflict, "event_finalized")
+}
+
+func TestSubmitResponse(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	other := api.createEvent(sampleEvent())
+	path := "/api/v1/events/" + created.ID + "/respond"
+	d1, d2 := created.Dates[0].ID, created.Dates[1].ID
+
+	answer := models.SubmitResponseRequest{
+		Name: "Kari",
+		Responses: []models.ResponseRequest{
+			{EventDateID: d1, Availability: models.AvailabilityYes},
+			{EventDateID: d2, Availability: models.AvailabilityMaybe},
+		},
+	}
+	rec := api.do(http.MethodPost, path, answer)
+	expectStatus(t, rec, http.StatusCreated)
+	var first models.SubmitResponseResult
+	decode(t, rec, &first)
+	if first.EditToken == "" || first.RespondentID == 0 || first.Message == "" {
+		t.Fatalf("first submission = %+v", first)
+	}
+
+	expectError(t, api.do(http.MethodPost, path, answer), http.StatusConflict, "respondent_exists")
+	expectError(t, api.do(http.MethodPost, path, answer, EditTokenHeader, "wrong"), http.StatusForbidden, "invalid_edit_token")
+
+	// The edit token lets Kari change the answers
+	answer.Responses[1].Availability = models.AvailabilityNo
+	rec = api.do(http.MethodPost, path, answer, EditTokenHeader, first.EditToken)
+	expectStatus(t, rec, http.StatusCreated)
+	var again models.SubmitResponseResult
+	decode(t, rec, &again)
+	if again.RespondentID != first.RespondentID || again.EditToken != "" {
+		t.Errorf("resubmission = %+v", again)
+	}
+
+	// Answers are still taken after the event is finalized
+	api.finalize(created, d1)
+	expectStatus(t, api.do(http.MethodPost, path, answerYes("Ola", created.Dates[2].ID)), http.StatusCreated)
+
+	rec = api.do(http.MethodPost, path, models.SubmitResponseRequest{
+		Name:      "Per",
+		Responses: []models.ResponseRequest{{EventDateID: other.Dates[0].ID, Availability: models.AvailabilityYes}},
+	})
+	expectError(t, rec, http.StatusUnprocessableEntity, "date_not_in_event")
+	rec = api.do(http.MethodPost, path, models.SubmitResponseRequest{
+		Name: "Per",
+		Responses: []models.ResponseRequest{
+			{EventDateID: d1, Availability: models.AvailabilityYes},
+			{EventDateID: d1, Availability: models.AvailabilityNo},
+		},
+	})
+	expectError(t, rec, http.StatusUnprocessableEntity, "duplicate_response")
+	expectError(t, api.do(http.MethodPost, path, models.SubmitResponseRequest{Name: ""}), http.StatusBadRequest, "validation_failed")
+	expectError(t, api.do(http.MethodPost, "/api/v1/events/missing/respond", answer), http.StatusNotFound, "event_not_found")
+
+	rec = api.do(http.MethodGet, "/api/v1/events/"+created.ID+"/results", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var results models.EventResults
+	decode(t, rec, &results)
+	if len(results.Respondents) != 2 {
+		t.Fatalf("got %d respondents, want 2", len(results.Respondents))
+	}
+	s1, s2 := results.Summary[d1], results.Summary[d2]
+	if s1.AvailableCount != 1 || s2.UnavailableCount != 1 || s2.MaybeCount != 0 || s1.NoAnswerCount != 1 {
+		t.Errorf("summary = %+v and %+v", s1, s2)
+	}
+
+	expectError(t, api.do(http.MethodGet, "/api/v1/events/missing/results", nil), http.StatusNotFound, "event_not_found")
+}
+
+func TestEventRateLimit(t *testing.T) {
+	api := newLimitedAPI(t, Limits{PerEvent: ratelimit.New(0.001, 1)})
+	created := api.createEvent(sampleEvent())
+	busy := "/api/v1/events/" + created.ID + "/respond"
+
+	expectStatus(t, api.do(http.MethodPost, busy, answerYes("Kari", created.Dates[0].ID)), http.StatusCreated)
+	expectError(t, api.do(http.MethodPost, busy, answerYes("Ola", created.Dates[0].ID)), http.StatusTooManyRequests, "rate_limited")
+
+	// Other events are not affected
+	quiet := api.createEvent(sampleEvent())
+	expectStatus(t, api.do(http.MethodPost, "/api/v1/events/"+quiet.ID+"/respond", answerYes("Ola", quiet.Dates[0].ID)), http.StatusCreated)
+}
+
+// answerYes is a submission that answers yes to a single date option
+func answerYes(name string, eventDateID int) models.SubmitResponseRequest {
+	return models.SubmitResponseRequest{
+		Name:      name,
+		Responses: []models.ResponseRequest{{EventDateID: eventDateID, Availability: models.AvailabilityYes}},
+	}
+}
+
+func TestViewerTimeZone(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	base := "/api/v1/events/" + created.ID
+
+	tests := []struct {
+		tz         string
+		start, end string
+	}{
+		{"", "2035-12-05T18:00:00+01:00", "2035-12-05T23:00:00+01:00"},
+		{"America/New_York", "2035-12-05T12:00:00-05:00", "2035-12-05T17:00:00-05:00"},
+		{"Asia/Kolkata", "2035-12-05T22:30:00+05:30", "2035-12-06T03:30:00+05:30"},
+		{"UTC", "2035-12-05T17:00:00Z", "2035-12-05T22:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run("tz="+tt.tz, func(t *testing.T) {
+			query := ""
+			if tt.tz != "" {
+				query = "?tz=" + tt.tz
+			}
+
+			var event models.Event
+			rec := api.do(http.MethodGet, base+query, nil)
+			expectStatus(t, rec, http.StatusOK)
+			decode(t, rec, &event)
+			var results models.EventResults
+			rec = api.do(http.MethodGet, base+"/results"+query, nil)
+			expectStatus(t, rec, http.StatusOK)
+			decode(t, rec, &results)
+
+			for _, date := range []models.EventDate{event.Dates[0], results.Event.Dates[0]} {
+				if date.Start == nil || date.End == nil {
+					t.Fatalf("date option %d has no instants", date.ID)
+				}
+				if got := date.Start.Format(time.RFC3339); got != tt.start {
+					t.Errorf("start = %s, want %s", got, tt.start)
+				}
+				if got := date.End.Format(time.RFC3339); got != tt.end {
+					t.Errorf("end = %s, want %s", got, tt.end)
+				}
+				// The wall clock fields stay in the event's own time zone
+				if date.Date != "2035-12-05" || date.StartTime != "18:00" {
+					t.Errorf("wall clock changed to %s %s", date.Date, date.StartTime)
+				}
+			}
+		})
+	}
+
+	expectError(t, api.do(http.MethodGet, base+"?tz=Mars/Olympus_Mons", nil), http.StatusBadRequest, "unknown_time_zone")
+	expectError(t, api.do(http.MethodGet, base+"/results?tz=Mars/Olympus_Mons", nil), http.StatusBadRequest, "unknown_time_zone")
+}
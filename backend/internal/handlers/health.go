@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// readyTimeout bounds how long a readiness check may wait on the database
+const readyTimeout = 2 * time.Second
+
+// ReadinessChecker reports whether the backing store can serve requests
+type ReadinessChecker interface {
+	Ready(ctx context.Context) error
+}
+
+// HealthHandler serves the probes used by load balancers and orchestrators
+type HealthHandler struct {
+	checker ReadinessChecker
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(checker ReadinessChecker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// Routes lists the probes. Mount answers other methods with 405.
+func (h *HealthHandler) Routes() []Route {
+	return []Route{
+		{http.MethodGet, "/healthz", noStore(h.HandleHealthz)},
+		{http.MethodGet, "/readyz", noStore(h.HandleReadyz)},
+		{http.MethodGet, "/version", noStore(h.HandleVersion)},
+	}
+}
+
+// HandleHealthz handles GET /healthz. It succeeds whenever the process is
+// able to answer at all.
+func (h *HealthHandler) HandleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleReadyz handles GET /readyz. It succeeds when the database answers
+// and its migrations are current.
+func (h *HealthHandler) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	if err := h.checker.Ready(ctx); err != nil {
+		slog.WarnContext(ctx, "readiness check failed", "error", err)
+		writeError(w, r, errNotReady)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// HandleVersion handles GET /version
+func (h *HealthHandler) HandleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, readBuildInfo())
+}
+
+// readBuildInfo collects the module version and VCS details embedded by the
+// go command
+func readBuildInfo() BuildInfo {
+	info := BuildInfo{Version: "unknown"}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.Version = bi.Main.Version
+	info.GoVersion = bi.GoVersion
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.Time = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}
+
+// noStore keeps probe answers out of caches
+func noStore(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		next(w, r)
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// checkerFunc adapts a function to ReadinessChecker
+type checkerFunc func(ctx context.Context) error
+
+func (f checkerFunc) Ready(ctx context.Context) error { return f(ctx) }
+
+func TestHealthRoutes(t *testing.T) {
+	var notReady error
+	mux := http.NewServeMux()
+	Mount(mux, NewHealthHandler(checkerFunc(func(context.Context) error { return notReady })).Routes(), nil)
+
+	tests := []struct {
+		method, path string
+		notReady     error
+		status       int
+		allow        string
+	}{
+		{http.MethodGet, "/healthz", nil, http.StatusOK, ""},
+		{http.MethodHead, "/healthz", nil, http.StatusOK, ""},
+		{http.MethodGet, "/readyz", nil, http.StatusOK, ""},
+		{http.MethodGet, "/readyz", errors.New("database is down"), http.StatusServiceUnavailable, ""},
+		{http.MethodGet, "/version", nil, http.StatusOK, ""},
+		{http.MethodPost, "/healthz", nil, http.StatusMethodNotAllowed, "GET, HEAD"},
+		{http.MethodDelete, "/readyz", nil, http.StatusMethodNotAllowed, "GET, HEAD"},
+		{http.MethodPut, "/version", nil, http.StatusMethodNotAllowed, "GET, HEAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			notReady = tt.notReady
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("Allow = %q, want %q", got, tt.allow)
+			}
+			if tt.status != http.StatusMethodNotAllowed && rec.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", rec.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// testAPI drives the event routes of a handler backed by a fresh MemoryStore
+type testAPI struct {
+	t       *testing.T
+	handler *EventHandler
+	mux     *http.ServeMux
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	return newLimitedAPI(t, Limits{})
+}
+
+// newLimitedAPI is newTestAPI with abuse limits
+func newLimitedAPI(t *testing.T, limits Limits) *testAPI {
+	t.Helper()
+	handler := NewEventHandler(database.NewMemoryStore(), limits)
+	mux := http.NewServeMux()
+	Mount(mux, handler.Routes(), nil)
+	Mount(mux, LegacyRoutes(handler.Routes()), nil)
+	mux.HandleFunc("/api/", NotFound)
+	return &testAPI{t: t, handler: handler, mux: mux}
+}
+
+// do sends a request with an optional JSON body and header pairs
+func (a *testAPI) do(method, path string, body any, headers ...string) *httptest.ResponseRecorder {
+	a.t.Helper()
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			a.t.Fatal(err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	a.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// createEvent creates an event and returns it with its admin token
+func (a *testAPI) createEvent(req models.CreateEventRequest) *models.CreateEventResponse {
+	a.t.Helper()
+	rec := a.do(http.MethodPost, "/api/v1/events", req)
+	expectStatus(a.t, rec, http.StatusCreated)
+
+	var created models.CreateEventResponse
+	decode(a.t, rec, &created)
+	return &created
+}
+
+// finalize finalizes an event on one of its date options
+func (a *testAPI) finalize(created *models.CreateEventResponse, eventDateID int) {
+	a.t.Helper()
+	rec := a.do(http.MethodPatch, "/api/v1/events/"+created.ID+"/finalize",
+		map[string]int{"event_date_id": eventDateID}, AdminTokenHeader, created.AdminToken)
+	expectStatus(a.t, rec, http.StatusOK)
+}
+
+// sampleEvent is an event with three date options well in the future
+func sampleEvent() models.CreateEventRequest {
+	return models.CreateEventRequest{
+		Name:     "Julebord",
+		TimeZone: "Europe/Oslo",
+		Dates: []models.CreateDateRequest{
+			{Date: "2035-12-05", StartTime: "18:00", EndTime: "23:00"},
+			{Date: "2035-12-12", StartTime: "18:00", EndTime: "23:00"},
+			{Date: "2035-12-19", StartTime: "17:30", EndTime: "22:00"},
+		},
+	}
+}
+
+// expectStatus fails the test if a response has an unexpected status
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, status, rec.Body)
+	}
+}
+
+// expectError checks a JSON error response's status and code
+func expectError(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	expectStatus(t, rec, status)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body models.ErrorResponse
+	decode(t, rec, &body)
+	if body.Code != code {
+		t.Errorf("code = %q, want %q", body.Code, code)
+	}
+}
+
+// decode reads a JSON response body into v
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", rec.Body, err)
+	}
+}
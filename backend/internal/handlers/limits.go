@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/metrics"
+	"github.com/jleikdra/finn-en-dato/backend/internal/ratelimit"
+)
+
+// Limits protects the endpoints that write to the database from abuse. The
+// zero value disables every limit.
+type Limits struct {
+	// PerClient limits creating events and submitting responses by client IP
+	PerClient *ratelimit.Limiter
+
+	// PerEvent limits submitting responses by event, however many clients
+	// are involved
+	PerEvent *ratelimit.Limiter
+
+	// TrustedProxies are the proxies whose X-Forwarded-For header is used to
+	// find the client IP
+	TrustedProxies ratelimit.Proxies
+
+	// MaxBodyBytes caps the size of JSON request bodies; 0 means no cap
+	MaxBodyBytes int64
+}
+
+// rateLimitError is returned when a client or event has used up its requests
+type rateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return "Too many requests"
+}
+
+// checkClientRate takes a token from the requesting client's bucket
+func (h *EventHandler) checkClientRate(r *http.Request) error {
+	ok, wait := h.limits.PerClient.Allow(h.limits.TrustedProxies.ClientIP(r), time.Now())
+	if !ok {
+		metrics.RateLimited.WithLabelValues("client").Inc()
+		return &rateLimitError{retryAfter: wait}
+	}
+	return nil
+}
+
+// checkEventRate takes a token from an event's bucket
+func (h *EventHandler) checkEventRate(eventID string) error {
+	ok, wait := h.limits.PerEvent.Allow(eventID, time.Now())
+	if !ok {
+		metrics.RateLimited.WithLabelValues("event").Inc()
+		return &rateLimitError{retryAfter: wait}
+	}
+	return nil
+}
+
+// decodeJSON reads the request body into v, refusing bodies over the size cap
+// before they are decoded
+func (h *EventHandler) decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	body := r.Body
+	if h.limits.MaxBodyBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.limits.MaxBodyBytes)
+	}
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return errBodyTooLarge
+		}
+		return errInvalidJSON
+	}
+	return nil
+}
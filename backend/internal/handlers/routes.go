@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// APIPrefix is the path every current API route starts with
+	APIPrefix = "/api/v1"
+
+	// legacyPrefix is where the API lived before it was versioned
+	legacyPrefix = "/api"
+)
+
+var (
+	// legacyDeprecated is when the unversioned routes were deprecated
+	legacyDeprecated = time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+
+	// legacySunset is when the unversioned routes may be removed
+	legacySunset = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// Route is one endpoint: a method and a ServeMux path pattern such as
+// /api/v1/events/{id}/results
+type Route struct {
+	Method  string
+	Pattern string
+	Handler http.HandlerFunc
+}
+
+// Routes lists every endpoint of the event API
+func (h *EventHandler) Routes() []Route {
+	return []Route{
+		{http.MethodPost, "/api/v1/events", h.createEvent},
+		{http.MethodGet, "/api/v1/events/{id}", h.getEvent},
+		{http.MethodPatch, "/api/v1/events/{id}", h.updateEvent},
+		{http.MethodDelete, "/api/v1/events/{id}", h.deleteEvent},
+		{http.MethodGet, "/api/v1/events/{id}/results", h.getEventResults},
+		{http.MethodGet, "/api/v1/events/{id}/event.ics", h.getEventICS},
+		{http.MethodGet, "/api/v1/events/{id}/options.ics", h.getOptionsICS},
+		{http.MethodPost, "/api/v1/events/{id}/respond", h.submitResponse},
+		{http.MethodPatch, "/api/v1/events/{id}/finalize", h.finalizeEvent},
+		{http.MethodPost, "/api/v1/events/{id}/dates", h.addEventDate},
+		{http.MethodDelete, "/api/v1/events/{id}/dates/{dateID}", h.removeEventDate},
+	}
+}
+
+// LegacyRoutes returns the routes under their old unversioned paths, e.g.
+// /api/events/{id} for /api/v1/events/{id}. Their responses carry
+// Deprecation and Sunset headers and link to the versioned path.
+func LegacyRoutes(routes []Route) []Route {
+	legacy := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		pattern, ok := strings.CutPrefix(route.Pattern, APIPrefix)
+		if !ok {
+			continue
+		}
+		legacy = append(legacy, Route{
+			Method:  route.Method,
+			Pattern: legacyPrefix + pattern,
+			Handler: deprecated(route.Handler),
+		})
+	}
+	return legacy
+}
+
+// Mount registers routes on mux. Every path also gets a handler for the
+// methods it does not accept, which answers 405 with an Allow header. If wrap
+// is not nil it is applied to each handler together with the methods its
+// path accepts, e.g. to add CORS.
+func Mount(mux *http.ServeMux, routes []Route, wrap func(next http.Handler, methods ...string) http.Handler) {
+	if wrap == nil {
+		wrap = func(next http.Handler, _ ...string) http.Handler { return next }
+	}
+
+	// Group the methods by path, keeping the table's order
+	var patterns []string
+	methods := make(map[string][]string)
+	for _, route := range routes {
+		if _, ok := methods[route.Pattern]; !ok {
+			patterns = append(patterns, route.Pattern)
+		}
+		methods[route.Pattern] = append(methods[route.Pattern], route.Method)
+	}
+
+	for _, route := range routes {
+		mux.Handle(route.Method+" "+route.Pattern, wrap(route.Handler, methods[route.Pattern]...))
+	}
+	for _, pattern := range patterns {
+		mux.Handle(pattern, wrap(methodNotAllowed(methods[pattern]), methods[pattern]...))
+	}
+}
+
+// NotFound answers requests for unknown API paths
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, errNotFound)
+}
+
+// deprecated adds the headers announcing that a legacy route will go away
+func deprecated(next http.HandlerFunc) http.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(legacyDeprecated.Unix(), 10)
+	sunset := legacySunset.Format(http.TimeFormat)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		successor := APIPrefix + strings.TrimPrefix(r.URL.EscapedPath(), legacyPrefix)
+
+		h := w.Header()
+		h.Set("Deprecation", deprecation)
+		h.Set("Sunset", sunset)
+		h.Add("Link", "<"+successor+`>; rel="successor-version"`)
+		next(w, r)
+	}
+}
+
+// methodNotAllowed answers requests using a method a path does not accept
+func methodNotAllowed(methods []string) http.HandlerFunc {
+	allowed := slices.Clone(methods)
+	if slices.Contains(allowed, http.MethodGet) {
+		// ServeMux routes HEAD to GET handlers
+		allowed = append(allowed, http.MethodHead)
+	}
+	allow := strings.Join(allowed, ", ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		writeError(w, r, errMethodNotAllowed)
+	}
+}
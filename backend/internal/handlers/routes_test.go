@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+)
+
+// allow is the Allow header expected on each path of the route table
+var allow = map[string]string{
+	"/api/v1/events":                     "POST",
+	"/api/v1/events/{id}":                "GET, PATCH, DELETE, HEAD",
+	"/api/v1/events/{id}/results":        "GET, HEAD",
+	"/api/v1/events/{id}/event.ics":      "GET, HEAD",
+	"/api/v1/events/{id}/options.ics":    "GET, HEAD",
+	"/api/v1/events/{id}/respond":        "POST",
+	"/api/v1/events/{id}/finalize":       "PATCH",
+	"/api/v1/events/{id}/dates":          "POST",
+	"/api/v1/events/{id}/dates/{dateID}": "DELETE",
+}
+
+var allMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// examplePath fills in a route pattern's wildcards
+func examplePath(pattern string) string {
+	return strings.NewReplacer("{id}", "abc123", "{dateID}", "7").Replace(pattern)
+}
+
+func TestRoutesResolve(t *testing.T) {
+	handler := NewEventHandler(database.NewMemoryStore(), Limits{})
+	routes := handler.Routes()
+	mux := http.NewServeMux()
+	Mount(mux, routes, nil)
+	Mount(mux, LegacyRoutes(routes), nil)
+
+	for _, route := range append(routes, LegacyRoutes(routes)...) {
+		req := httptest.NewRequest(route.Method, examplePath(route.Pattern), nil)
+		if _, pattern := mux.Handler(req); pattern != route.Method+" "+route.Pattern {
+			t.Errorf("%s %s resolves to %q", route.Method, req.URL.Path, pattern)
+		}
+		if route.Method == http.MethodGet {
+			req.Method = http.MethodHead
+			if _, pattern := mux.Handler(req); pattern != route.Method+" "+route.Pattern {
+				t.Errorf("HEAD %s resolves to %q", req.URL.Path, pattern)
+			}
+		}
+	}
+
+	for _, route := range routes {
+		if _, ok := allow[route.Pattern]; !ok {
+			t.Errorf("route %s %s is missing from the allow table", route.Method, route.Pattern)
+		}
+		if !strings.HasPrefix(route.Pattern, APIPrefix+"/") {
+			t.Errorf("route %s is not under %s", route.Pattern, APIPrefix)
+		}
+	}
+	if n := len(LegacyRoutes(routes)); n != len(routes) {
+		t.Errorf("%d legacy routes for %d routes", n, len(routes))
+	}
+}
+
+func TestUnknownPaths(t *testing.T) {
+	api := newTestAPI(t)
+	for _, path := range []string{
+		"/api/v1/events/x/results/extra",
+		"/api/v1/events/x/dates/1/extra",
+		"/api/v1/nothing",
+		"/api/v2/events",
+		"/api/events/x/results/extra",
+		"/api/",
+	} {
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			t.Run(method+" "+path, func(t *testing.T) {
+				expectError(t, api.do(method, path, nil), http.StatusNotFound, "not_found")
+			})
+		}
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	api := newTestAPI(t)
+	routes := api.handler.Routes()
+
+	patterns := make(map[string][]string)
+	for _, route := range append(routes, LegacyRoutes(routes)...) {
+		patterns[route.Pattern] = append(patterns[route.Pattern], route.Method)
+	}
+
+	for pattern, methods := range patterns {
+		current := pattern
+		if !strings.HasPrefix(pattern, APIPrefix+"/") {
+			current = APIPrefix + strings.TrimPrefix(pattern, legacyPrefix)
+		}
+		want, ok := allow[current]
+		if !ok {
+			t.Errorf("no Allow header known for %s", pattern)
+			continue
+		}
+		for _, method := range allMethods {
+			accepted := slices.Contains(methods, method) ||
+				method == http.MethodHead && slices.Contains(methods, http.MethodGet)
+			if accepted {
+				continue
+			}
+
+			path := examplePath(pattern)
+			t.Run(method+" "+path, func(t *testing.T) {
+				rec := api.do(method, path, nil)
+				expectError(t, rec, http.StatusMethodNotAllowed, "method_not_allowed")
+				if got := rec.Header().Get("Allow"); got != want {
+					t.Errorf("Allow = %q, want %q", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestLegacyRoutesDeprecated(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createEvent(sampleEvent())
+	routes := api.handler.Routes()
+
+	for _, route := range LegacyRoutes(routes) {
+		path := strings.NewReplacer("{id}", created.ID, "{dateID}", "999999").Replace(route.Pattern)
+		t.Run(route.Method+" "+route.Pattern, func(t *testing.T) {
+			rec := api.do(route.Method, path, nil)
+			h := rec.Header()
+			if got := h.Get("Deprecation"); got != "@1792108800" {
+				t.Errorf("Deprecation = %q, want @1792108800", got)
+			}
+			if got := h.Get("Sunset"); got != "Fri, 30 Apr 2027 00:00:00 GMT" {
+				t.Errorf("Sunset = %q", got)
+			}
+			want := "<" + APIPrefix + strings.TrimPrefix(path, legacyPrefix) + `>; rel="successor-version"`
+			if got := h.Values("Link"); !slices.Contains(got, want) {
+				t.Errorf("Link = %q, want %q", got, want)
+			}
+		})
+	}
+
+	// The legacy paths behave like the versioned ones
+	legacy := api.do(http.MethodGet, "/api/events/"+created.ID, nil)
+	current := api.do(http.MethodGet, "/api/v1/events/"+created.ID, nil)
+	expectStatus(t, legacy, http.StatusOK)
+	if legacy.Body.String() != current.Body.String() {
+		t.Errorf("legacy body %s differs from %s", legacy.Body, current.Body)
+	}
+
+	for _, route := range routes {
+		path := strings.NewReplacer("{id}", created.ID, "{dateID}", "999999").Replace(route.Pattern)
+		rec := api.do(route.Method, path, nil)
+		for _, header := range []string{"Deprecation", "Sunset", "Link"} {
+			if got := rec.Header().Get(header); got != "" {
+				t.Errorf("%s %s sends %s: %s", route.Method, path, header, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package ical writes iCalendar (RFC 5545) documents.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event status values for VEVENT STATUS
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProdID string
+	Method string // optional, e.g. PUBLISH
+	Name   string // optional display name for subscribed calendars
+	Events []Event
+}
+
+// Event is a VEVENT component. Start and End are written as floating local
+// times unless they are in UTC.
+type Event struct {
+	UID         string
+	Sequence    int
+	Status      string
+	Summary     string
+	Description string
+	URL         string
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+}
+
+// WriteTo encodes the calendar with CRLF line endings and folded lines
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	l := lineWriter{buf: &buf}
+
+	l.prop("BEGIN", "VCALENDAR")
+	l.prop("VERSION", "2.0")
+	l.prop("PRODID", c.ProdID)
+	l.prop("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		l.prop("METHOD", c.Method)
+	}
+	if c.Name != "" {
+		l.prop("X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		l.prop("BEGIN", "VEVENT")
+		l.prop("UID", e.UID)
+		l.prop("DTSTAMP", formatUTC(e.Stamp))
+		l.prop("DTSTART", formatDateTime(e.Start))
+		l.prop("DTEND", formatDateTime(e.End))
+		l.prop("SEQUENCE", fmt.Sprint(e.Sequence))
+		if e.Status != "" {
+			l.prop("STATUS", e.Status)
+		}
+		l.prop("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			l.prop("DESCRIPTION", escapeText(e.Description))
+		}
+		if e.URL != "" {
+			l.prop("URL", e.URL)
+		}
+		l.prop("END", "VEVENT")
+	}
+
+	l.prop("END", "VCALENDAR")
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// formatUTC formats t as a UTC date-time, e.g. 20300101T090000Z
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// formatDateTime formats t as a UTC date-time if it is in UTC and as a
+// floating local date-time otherwise
+func formatDateTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return formatUTC(t)
+	}
+	return t.Format("20060102T150405")
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// lineWriter writes content lines folded at 75 octets
+type lineWriter struct {
+	buf *bytes.Buffer
+}
+
+// prop writes a single NAME:value content line
+func (l lineWriter) prop(name, value string) {
+	line := name + ":" + value
+
+	// Fold long lines without splitting multi-byte characters. Continuation
+	// lines start with a space, which counts towards their 75 octets.
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		l.buf.WriteString(line[:cut])
+		l.buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+
+	l.buf.WriteString(line)
+	l.buf.WriteString("\r\n")
+}
+
+// isRuneStart reports whether b begins a UTF-8 encoded character
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,147 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/golden"
+)
+
+func TestCalendarWriteTo(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Date(2030, time.January, 2, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cal  Calendar
+	}{
+		{
+			name: "simple.ics",
+			cal: Calendar{
+				ProdID: "-//finn-en-dato//finn-en-dato//NO",
+				Method: "PUBLISH",
+				Events: []Event{{
+					UID:      "abc-1@finn-en-dato",
+					Sequence: 2,
+					Status:   StatusConfirmed,
+					Summary:  "Julebord",
+					Stamp:    stamp,
+					Start:    time.Date(2030, time.December, 12, 17, 0, 0, 0, time.UTC),
+					End:      time.Date(2030, time.December, 12, 22, 0, 0, 0, time.UTC),
+				}},
+			},
+		},
+		{
+			name: "escaping.ics",
+			cal: Calendar{
+				ProdID: "-//finn-en-dato//finn-en-dato//NO",
+				Name:   "Planning; part 1, draft",
+				Events: []Event{{
+					UID:         "abc-2@finn-en-dato",
+					Status:      StatusTentative,
+					Summary:     `Lunch, drinks; and a \ backslash`,
+					Description: "First line\nSecond line\r\nThird line",
+					URL:         "https://example.com/event/abc?x=1,2;3",
+					Stamp:       stamp,
+					// Not UTC, so written as a floating local time
+					Start: time.Date(2030, time.June, 1, 12, 0, 0, 0, oslo),
+					End:   time.Date(2030, time.June, 1, 13, 30, 0, 0, oslo),
+				}},
+			},
+		},
+		{
+			name: "folding.ics",
+			cal: Calendar{
+				ProdID: "-//finn-en-dato//finn-en-dato//NO",
+				Events: []Event{{
+					UID:         "abc-3@finn-en-dato",
+					Status:      StatusCancelled,
+					Summary:     strings.Repeat("Sommerfest på tårnet ", 6),
+					Description: strings.Repeat("Blåbærsyltetøy og rømmegrøt 🍓 ", 5),
+					Stamp:       stamp,
+					Start:       time.Date(2030, time.July, 1, 16, 0, 0, 0, time.UTC),
+					End:         time.Date(2030, time.July, 1, 20, 0, 0, 0, time.UTC),
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := tt.cal.WriteTo(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("WriteTo returned %d, wrote %d bytes", n, buf.Len())
+			}
+			checkContentLines(t, buf.Bytes())
+			golden.Check(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+// checkContentLines checks the framing rules of RFC 5545 section 3.1 that are
+// easy to break without changing how the calendar looks in a text editor
+func checkContentLines(t *testing.T, out []byte) {
+	t.Helper()
+	if !bytes.HasSuffix(out, []byte("\r\n")) {
+		t.Fatal("output does not end with CRLF")
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\r\n"), "\r\n")
+	for i, line := range lines {
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("line %d has a bare CR or LF: %q", i+1, line)
+		}
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets long: %q", i+1, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a multi-byte character: %q", i+1, line)
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a,b", `a\,b`},
+		{"a;b", `a\;b`},
+		{`a\b`, `a\\b`},
+		{"a\nb", `a\nb`},
+		{"a\r\nb", `a\nb`},
+		{`\n`, `\\n`},
+		{"æøå", "æøå"},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.in); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFoldKeepsCharacters(t *testing.T) {
+	// Three-byte characters placed so that a cut at 75 octets would land
+	// inside one of them, whatever the prefix length
+	for prefix := 0; prefix < 3; prefix++ {
+		value := strings.Repeat("x", prefix) + strings.Repeat("€", 60)
+
+		var buf bytes.Buffer
+		lineWriter{buf: &buf}.prop("SUMMARY", value)
+		checkContentLines(t, buf.Bytes())
+
+		unfolded := strings.ReplaceAll(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n ", "")
+		if unfolded != "SUMMARY:"+value {
+			t.Errorf("prefix %d: unfolding gives %q", prefix, unfolded)
+		}
+	}
+}
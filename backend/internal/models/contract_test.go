@@ -0,0 +1,150 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/golden"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// contractEvent is an event with every field set
+func contractEvent(t *testing.T) Event {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2035, 11, 1, 9, 30, 0, 0, time.UTC)
+	event := Event{
+		ID:              "3f2a9c1e-7b4d-4e8a-9f60-2d1c5b7a8e90",
+		Name:            "Julebord",
+		TimeZone:        "Europe/Oslo",
+		CreatedAt:       created,
+		FinalizedDateID: ptr(12),
+		FinalizedAt:     ptr(created.Add(48 * time.Hour)),
+		UpdatedAt:       ptr(created.Add(48 * time.Hour)),
+		Sequence:        3,
+		Dates: []EventDate{
+			{ID: 11, EventID: "3f2a9c1e-7b4d-4e8a-9f60-2d1c5b7a8e90", Date: "2035-12-05", StartTime: "18:00", EndTime: "23:00"},
+			{ID: 12, EventID: "3f2a9c1e-7b4d-4e8a-9f60-2d1c5b7a8e90", Date: "2035-12-12", StartTime: "18:00", EndTime: "23:00"},
+		},
+	}
+	if err := event.Localize(oslo); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// contractResults are the results of contractEvent with two respondents
+func contractResults(t *testing.T) EventResults {
+	event := contractEvent(t)
+	created := time.Date(2035, 11, 2, 20, 15, 0, 0, time.UTC)
+	return EventResults{
+		Event: event,
+		Respondents: []Respondent{
+			{
+				ID: 5, EventID: event.ID, Name: "Kari", CreatedAt: created,
+				Responses: []Response{
+					{ID: 21, RespondentID: 5, EventDateID: 11, Availability: AvailabilityYes, Available: true},
+					{ID: 22, RespondentID: 5, EventDateID: 12, Availability: AvailabilityMaybe},
+				},
+			},
+			{
+				ID: 6, EventID: event.ID, Name: "Ola", CreatedAt: created.Add(time.Hour),
+				Responses: []Response{
+					{ID: 23, RespondentID: 6, EventDateID: 11, Availability: AvailabilityNo},
+				},
+			},
+		},
+		Summary: map[int]AvailabilitySummary{
+			11: {
+				EventDateID: 11, AvailableCount: 1, UnavailableCount: 1,
+				AvailableNames: []string{"Kari"}, MaybeNames: []string{}, NoAnswerNames: []string{},
+			},
+			12: {
+				EventDateID: 12, MaybeCount: 1, NoAnswerCount: 1,
+				AvailableNames: []string{}, MaybeNames: []string{"Kari"}, NoAnswerNames: []string{"Ola"},
+			},
+		},
+	}
+}
+
+// TestJSONContract pins the JSON clients receive. A failure here means the
+// API changed shape: update the golden file only for a compatible change,
+// and update openapi.json with it.
+func TestJSONContract(t *testing.T) {
+	results := contractResults(t)
+	tests := []struct {
+		file  string
+		value any
+		empty func() any // decodes the golden file back
+	}{
+		{"event.json", contractEvent(t), func() any { return &Event{} }},
+		{"event_minimal.json", Event{
+			ID:        "3f2a9c1e-7b4d-4e8a-9f60-2d1c5b7a8e90",
+			Name:      "Julebord",
+			TimeZone:  "Europe/Oslo",
+			CreatedAt: time.Date(2035, 11, 1, 9, 30, 0, 0, time.UTC),
+		}, func() any { return &Event{} }},
+		{"results.json", results, func() any { return &EventResults{} }},
+		{"summary.json", results.Summary[12], func() any { return &AvailabilitySummary{} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, err := json.MarshalIndent(tt.value, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden.Check(t, tt.file, append(got, '\n'))
+
+			// Clients written against the golden file decode it to the same value
+			decoded := tt.empty()
+			if err := json.Unmarshal(got, decoded); err != nil {
+				t.Fatal(err)
+			}
+			again, err := json.MarshalIndent(decoded, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(again, got) {
+				t.Errorf("round trip changed the JSON:\n%s", again)
+			}
+		})
+	}
+}
+
+// TestSummaryKeys checks that the summary is keyed by event date ID, which
+// JSON turns into strings
+func TestSummaryKeys(t *testing.T) {
+	data, err := json.Marshal(contractResults(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Summary map[string]json.RawMessage `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(raw.Summary))
+	for key := range raw.Summary {
+		keys = append(keys, key)
+	}
+	if len(keys) != 2 || raw.Summary["11"] == nil || raw.Summary["12"] == nil {
+		t.Errorf("summary keys = %q, want 11 and 12", keys)
+	}
+
+	var summary AvailabilitySummary
+	if err := json.Unmarshal(raw.Summary["11"], &summary); err != nil {
+		t.Fatal(err)
+	}
+	if want := contractResults(t).Summary[11]; !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary 11 = %+v, want %+v", summary, want)
+	}
+}
@@ -9,8 +9,12 @@ import (
 type Event struct {
 	ID               string     `json:"id"`
 	Name             string     `json:"name"`
+	TimeZone         string     `json:"time_zone"` // IANA name the date options' wall clock times are in
 	CreatedAt        time.Time  `json:"created_at"`
 	FinalizedDateID  *int       `json:"finalized_date_id,omitempty"`
+	FinalizedAt      *time.Time `json:"finalized_at,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+	Sequence         int        `json:"sequence"` // incremented on every change, used as the iCalendar SEQUENCE
 	Dates            []EventDate `json:"dates,omitempty"`
 	Respondents      []Respondent `json:"respondents,omitempty"`
 }
@@ -22,6 +26,11 @@ type EventDate struct {
 	Date      string `json:"date"`      // YYYY-MM-DD format
 	StartTime string `json:"start_time"` // HH:MM format
 	EndTime   string `json:"end_time"`   // HH:MM format
+
+	// Start and End are the option's instants with a UTC offset, in the
+	// event's time zone or the zone the viewer asked for
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
 }
 
 // Respondent represents someone who can respond to an event
@@ -33,18 +42,55 @@ type Respondent struct {
 	Responses []Response `json:"responses,omitempty"`
 }
 
+// Availability is a respondent's answer for a single event date
+type Availability string
+
+const (
+	AvailabilityYes   Availability = "yes"
+	AvailabilityMaybe Availability = "maybe" // could make it if necessary
+	AvailabilityNo    Availability = "no"
+)
+
+// Valid reports whether a is one of the known availability values
+func (a Availability) Valid() bool {
+	switch a {
+	case AvailabilityYes, AvailabilityMaybe, AvailabilityNo:
+		return true
+	}
+	return false
+}
+
 // Response represents a respondent's availability for a specific event date
 type Response struct {
-	ID            int  `json:"id"`
-	RespondentID  int  `json:"respondent_id"`
-	EventDateID   int  `json:"event_date_id"`
-	Available     bool `json:"available"`
+	ID           int          `json:"id"`
+	RespondentID int          `json:"respondent_id"`
+	EventDateID  int          `json:"event_date_id"`
+	Availability Availability `json:"availability"`
+	Available    bool         `json:"available"` // true only for AvailabilityYes, kept for older clients
 }
 
 // CreateEventRequest represents the request payload for creating a new event
 type CreateEventRequest struct {
-	Name  string             `json:"name"`
-	Dates []CreateDateRequest `json:"dates"`
+	Name     string             `json:"name"`
+	TimeZone string             `json:"time_zone"` // IANA name, defaults to DefaultTimeZone
+	Dates    []CreateDateRequest `json:"dates"`
+}
+
+// CreateEventResponse is returned once when an event is created. AdminToken
+// must be presented to perform organizer-only operations on the event.
+type CreateEventResponse struct {
+	Event
+	AdminToken string `json:"admin_token"`
+}
+
+// UpdateEventRequest represents the request payload for editing an event
+type UpdateEventRequest struct {
+	Name string `json:"name"`
+}
+
+// FinalizeEventRequest picks the date option an event is finalized on
+type FinalizeEventRequest struct {
+	EventDateID int `json:"event_date_id"`
 }
 
 // CreateDateRequest represents a date option when creating an event
@@ -60,10 +106,33 @@ type SubmitResponseRequest struct {
 	Responses []ResponseRequest `json:"responses"`
 }
 
-// ResponseRequest represents a single availability response
+// SubmitResponseResult is returned after submitting availability. EditToken is
+// only set on the first submission for a respondent and must be presented to
+// change their responses later.
+type SubmitResponseResult struct {
+	Message      string `json:"message"`
+	RespondentID int    `json:"respondent_id"`
+	EditToken    string `json:"edit_token,omitempty"`
+}
+
+// ResponseRequest represents a single availability response. Older clients
+// send only Available; Availability takes precedence when set.
 type ResponseRequest struct {
-	EventDateID int  `json:"event_date_id"`
-	Available   bool `json:"available"`
+	EventDateID  int          `json:"event_date_id"`
+	Availability Availability `json:"availability,omitempty"`
+	Available    bool         `json:"available"`
+}
+
+// ResolvedAvailability returns the requested availability, falling back to
+// the legacy Available flag when Availability is not set
+func (r ResponseRequest) ResolvedAvailability() Availability {
+	if r.Availability != "" {
+		return r.Availability
+	}
+	if r.Available {
+		return AvailabilityYes
+	}
+	return AvailabilityNo
 }
 
 // EventResults represents aggregated results for an event
@@ -79,6 +148,17 @@ type AvailabilitySummary struct {
 	AvailableCount  int      `json:"available_count"`
 	UnavailableCount int     `json:"unavailable_count"`
 	AvailableNames  []string `json:"available_names"`
+	MaybeCount      int      `json:"maybe_count"`
+	MaybeNames      []string `json:"maybe_names"`
+	NoAnswerCount   int      `json:"no_answer_count"` // respondents who have not answered this date yet
+	NoAnswerNames   []string `json:"no_answer_names"`
+}
+
+// ErrorResponse is the body of every API error response
+type ErrorResponse struct {
+	Code    string `json:"code"`              // stable machine readable code, e.g. "event_not_found"
+	Message string `json:"message"`           // human readable description
+	Details any    `json:"details,omitempty"` // optional structured context, e.g. per-field validation errors
 }
 
 // NullString helper for database nullable strings
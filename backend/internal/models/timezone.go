@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimeZone is used for events created without a time zone, including
+// every event from before time zones were stored
+const DefaultTimeZone = "Europe/Oslo"
+
+// Location loads the event's IANA time zone
+func (e *Event) Location() (*time.Location, error) {
+	name := e.TimeZone
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	return time.LoadLocation(name)
+}
+
+// Localize fills in Start and End for every date option, expressed in loc.
+// Date options whose stored date or times cannot be parsed are left without
+// Start and End.
+func (e *Event) Localize(loc *time.Location) error {
+	eventLoc, err := e.Location()
+	if err != nil {
+		return fmt.Errorf("invalid event time zone %q: %w", e.TimeZone, err)
+	}
+
+	for i := range e.Dates {
+		start, end, err := e.Dates[i].Range(eventLoc)
+		if err != nil {
+			e.Dates[i].Start, e.Dates[i].End = nil, nil
+			continue
+		}
+		start, end = start.In(loc), end.In(loc)
+		e.Dates[i].Start, e.Dates[i].End = &start, &end
+	}
+
+	return nil
+}
+
+// Range returns the start and end instants of a date option whose wall clock
+// times are in loc. An end time at or before the start time means the option
+// ends on the following day.
+func (d EventDate) Range(loc *time.Location) (time.Time, time.Time, error) {
+	day, err := time.Parse("2006-01-02", d.Date)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date of date option %d: %w", d.ID, err)
+	}
+	startClock, err := time.Parse("15:04", d.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start of date option %d: %w", d.ID, err)
+	}
+	endClock, err := time.Parse("15:04", d.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end of date option %d: %w", d.ID, err)
+	}
+
+	endDay := day
+	if endClock.Hour()*60+endClock.Minute() <= startClock.Hour()*60+startClock.Minute() {
+		endDay = day.AddDate(0, 0, 1)
+	}
+
+	start, _ := ResolveLocalTime(day.Year(), day.Month(), day.Day(), startClock.Hour(), startClock.Minute(), loc)
+	end, _ := ResolveLocalTime(endDay.Year(), endDay.Month(), endDay.Day(), endClock.Hour(), endClock.Minute(), loc)
+	return start, end, nil
+}
+
+// ResolveLocalTime converts a wall clock time in loc to an instant following
+// RFC 5545: an ambiguous time during a backwards DST transition refers to its
+// first occurrence, and a time skipped by a forwards transition is read with
+// the UTC offset from before the gap. exists is false for skipped times.
+func ResolveLocalTime(year int, month time.Month, day, hour, min int, loc *time.Location) (t time.Time, exists bool) {
+	wall := time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+
+	// Offsets in effect a day before and after cover any single transition
+	_, offsetBefore := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, offsetAfter := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var earliest time.Time
+	for _, offset := range []int{offsetBefore, offsetAfter} {
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if !sameWallClock(candidate, wall) {
+			continue
+		}
+		if earliest.IsZero() || candidate.Before(earliest) {
+			earliest = candidate
+		}
+	}
+
+	if !earliest.IsZero() {
+		return earliest, true
+	}
+
+	// Skipped by a forwards transition
+	return wall.Add(-time.Duration(offsetBefore) * time.Second).In(loc), false
+}
+
+// sameWallClock reports whether t shows the same date and time as wall
+func sameWallClock(t, wall time.Time) bool {
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := wall.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2 && t.Hour() == wall.Hour() && t.Minute() == wall.Minute()
+}
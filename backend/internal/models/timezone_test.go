@@ -0,0 +1,153 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestResolveLocalTime(t *testing.T) {
+	tests := []struct {
+		name   string
+		zone   string
+		wall   time.Time // wall clock time, read in zone
+		want   string    // instant in UTC
+		exists bool
+	}{
+		{"oslo winter", "Europe/Oslo", time.Date(2025, 1, 15, 18, 0, 0, 0, time.UTC), "2025-01-15T17:00:00Z", true},
+		{"oslo summer", "Europe/Oslo", time.Date(2025, 7, 15, 18, 0, 0, 0, time.UTC), "2025-07-15T16:00:00Z", true},
+		{"oslo before gap", "Europe/Oslo", time.Date(2025, 3, 30, 1, 59, 0, 0, time.UTC), "2025-03-30T00:59:00Z", true},
+		{"oslo gap start", "Europe/Oslo", time.Date(2025, 3, 30, 2, 0, 0, 0, time.UTC), "2025-03-30T01:00:00Z", false},
+		{"oslo in gap", "Europe/Oslo", time.Date(2025, 3, 30, 2, 30, 0, 0, time.UTC), "2025-03-30T01:30:00Z", false},
+		{"oslo after gap", "Europe/Oslo", time.Date(2025, 3, 30, 3, 0, 0, 0, time.UTC), "2025-03-30T01:00:00Z", true},
+		{"oslo ambiguous", "Europe/Oslo", time.Date(2025, 10, 26, 2, 30, 0, 0, time.UTC), "2025-10-26T00:30:00Z", true},
+		{"oslo after fold", "Europe/Oslo", time.Date(2025, 10, 26, 3, 0, 0, 0, time.UTC), "2025-10-26T02:00:00Z", true},
+		{"new york in gap", "America/New_York", time.Date(2025, 3, 9, 2, 30, 0, 0, time.UTC), "2025-03-09T07:30:00Z", false},
+		{"new york after gap", "America/New_York", time.Date(2025, 3, 9, 3, 0, 0, 0, time.UTC), "2025-03-09T07:00:00Z", true},
+		{"new york ambiguous", "America/New_York", time.Date(2025, 11, 2, 1, 30, 0, 0, time.UTC), "2025-11-02T05:30:00Z", true},
+		{"new york after fold", "America/New_York", time.Date(2025, 11, 2, 2, 0, 0, 0, time.UTC), "2025-11-02T07:00:00Z", true},
+		{"utc", "UTC", time.Date(2025, 3, 30, 2, 30, 0, 0, time.UTC), "2025-03-30T02:30:00Z", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := mustLoad(t, tt.zone)
+			w := tt.wall
+			got, exists := ResolveLocalTime(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), loc)
+			if s := got.UTC().Format(time.RFC3339); s != tt.want || exists != tt.exists {
+				t.Errorf("ResolveLocalTime(%s) = %s, %t; want %s, %t", w.Format("2006-01-02 15:04"), s, exists, tt.want, tt.exists)
+			}
+			if got.Location() != loc {
+				t.Errorf("result is in %v, want %v", got.Location(), loc)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		zone       string
+		date       EventDate
+		start, end string // in RFC 3339 with the zone's offset
+	}{
+		{
+			name:  "evening",
+			zone:  "Europe/Oslo",
+			date:  EventDate{Date: "2035-12-05", StartTime: "18:00", EndTime: "23:00"},
+			start: "2035-12-05T18:00:00+01:00", end: "2035-12-05T23:00:00+01:00",
+		},
+		{
+			name:  "past midnight",
+			zone:  "Europe/Oslo",
+			date:  EventDate{Date: "2035-12-31", StartTime: "22:00", EndTime: "02:00"},
+			start: "2035-12-31T22:00:00+01:00", end: "2036-01-01T02:00:00+01:00",
+		},
+		{
+			name:  "end equals start",
+			zone:  "UTC",
+			date:  EventDate{Date: "2035-06-01", StartTime: "12:00", EndTime: "12:00"},
+			start: "2035-06-01T12:00:00Z", end: "2035-06-02T12:00:00Z",
+		},
+		{
+			name:  "across spring forward",
+			zone:  "Europe/Oslo",
+			date:  EventDate{Date: "2025-03-30", StartTime: "01:00", EndTime: "04:00"},
+			start: "2025-03-30T01:00:00+01:00", end: "2025-03-30T04:00:00+02:00",
+		},
+		{
+			name:  "starts in gap",
+			zone:  "America/New_York",
+			date:  EventDate{Date: "2025-03-09", StartTime: "02:30", EndTime: "05:00"},
+			start: "2025-03-09T03:30:00-04:00", end: "2025-03-09T05:00:00-04:00",
+		},
+		{
+			name:  "ends in fold",
+			zone:  "America/New_York",
+			date:  EventDate{Date: "2025-11-01", StartTime: "22:00", EndTime: "01:30"},
+			start: "2025-11-01T22:00:00-04:00", end: "2025-11-02T01:30:00-04:00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := tt.date.Range(mustLoad(t, tt.zone))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := start.Format(time.RFC3339); got != tt.start {
+				t.Errorf("start = %s, want %s", got, tt.start)
+			}
+			if got := end.Format(time.RFC3339); got != tt.end {
+				t.Errorf("end = %s, want %s", got, tt.end)
+			}
+		})
+	}
+}
+
+func TestRangeInvalid(t *testing.T) {
+	for _, date := range []EventDate{
+		{Date: "2035-13-45", StartTime: "18:00", EndTime: "20:00"},
+		{Date: "2035-12-05", StartTime: "25:99", EndTime: "20:00"},
+		{Date: "2035-12-05", StartTime: "18:00", EndTime: "8pm"},
+	} {
+		if _, _, err := date.Range(time.UTC); err == nil {
+			t.Errorf("Range(%+v) succeeded", date)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	event := Event{
+		TimeZone: "America/New_York",
+		Dates: []EventDate{
+			{ID: 1, Date: "2035-07-04", StartTime: "20:00", EndTime: "23:00"},
+			{ID: 2, Date: "broken", StartTime: "20:00", EndTime: "23:00"},
+		},
+	}
+	if err := event.Localize(mustLoad(t, "Europe/Oslo")); err != nil {
+		t.Fatal(err)
+	}
+	if got := event.Dates[0].Start.Format(time.RFC3339); got != "2035-07-05T02:00:00+02:00" {
+		t.Errorf("start = %s, want it in Oslo time", got)
+	}
+	if event.Dates[1].Start != nil || event.Dates[1].End != nil {
+		t.Error("a date option that cannot be parsed was localized")
+	}
+
+	event.TimeZone = "Mars/Olympus_Mons"
+	if err := event.Localize(time.UTC); err == nil {
+		t.Error("Localize accepted an unknown event time zone")
+	}
+
+	legacy := Event{}
+	if loc, err := legacy.Location(); err != nil || loc.String() != DefaultTimeZone {
+		t.Errorf("Location() of an event without a time zone = %v, %v", loc, err)
+	}
+}
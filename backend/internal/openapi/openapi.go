@@ -0,0 +1,29 @@
+// Package openapi serves the OpenAPI 3.1 description of the event API, so
+// clients can generate types instead of copying them by hand.
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"net/http"
+	"time"
+)
+
+// Spec is the OpenAPI document. Keep it in step with handlers.Routes and the
+// models package when either changes; the tests compare them.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// loaded is when the process started, used as the document's modification
+// time since it only changes with the binary
+var loaded = time.Now()
+
+// Handler serves the document as JSON
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		http.ServeContent(w, r, "openapi.json", loaded, bytes.NewReader(Spec))
+	})
+}
@@ -0,0 +1,242 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/database"
+	"github.com/jleikdra/finn-en-dato/backend/internal/handlers"
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+	"github.com/jleikdra/finn-en-dato/backend/internal/validation"
+)
+
+// document is the part of the OpenAPI document the tests compare
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas    map[string]schema    `json:"schemas"`
+		Parameters map[string]parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+type schema struct {
+	Properties map[string]json.RawMessage `json:"properties"`
+	Required   []string                   `json:"required"`
+	AllOf      []schema                   `json:"allOf"`
+	Ref        string                     `json:"$ref"`
+}
+
+type parameter struct {
+	Ref  string `json:"$ref"`
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+// schemaTypes maps each object schema to the Go type encoded or decoded for it
+var schemaTypes = map[string]any{
+	"Event":                 models.Event{},
+	"EventDate":             models.EventDate{},
+	"Respondent":            models.Respondent{},
+	"Response":              models.Response{},
+	"CreateEventRequest":    models.CreateEventRequest{},
+	"CreateEventResponse":   models.CreateEventResponse{},
+	"UpdateEventRequest":    models.UpdateEventRequest{},
+	"FinalizeEventRequest":  models.FinalizeEventRequest{},
+	"CreateDateRequest":     models.CreateDateRequest{},
+	"SubmitResponseRequest": models.SubmitResponseRequest{},
+	"ResponseRequest":       models.ResponseRequest{},
+	"SubmitResponseResult":  models.SubmitResponseResult{},
+	"EventResults":          models.EventResults{},
+	"AvailabilitySummary":   models.AvailabilitySummary{},
+	"ErrorResponse":         models.ErrorResponse{},
+	"FieldError":            validation.FieldError{},
+}
+
+// responseSchemas are sent by the server, so a property is required exactly
+// when its field is always encoded
+var responseSchemas = []string{
+	"Event", "EventDate", "Respondent", "Response", "SubmitResponseResult",
+	"EventResults", "AvailabilitySummary", "ErrorResponse", "FieldError",
+}
+
+// scalarSchemas are not objects and have no Go struct
+var scalarSchemas = []string{"Availability", "Clock", "Message"}
+
+func load(t *testing.T) document {
+	t.Helper()
+	var doc document
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		t.Fatalf("invalid openapi.json: %v", err)
+	}
+	return doc
+}
+
+// jsonFields returns the JSON names of a struct's fields, including those of
+// embedded structs, and whether each is omitted when empty
+func jsonFields(typ reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			for embedded, omit := range jsonFields(field.Type) {
+				fields[embedded] = omit
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = slices.Contains(strings.Split(opts, ","), "omitempty")
+	}
+	return fields
+}
+
+// properties collects a schema's properties, following allOf references
+func (doc document) properties(t *testing.T, s schema) map[string]bool {
+	t.Helper()
+	props := make(map[string]bool)
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		target, ok := doc.Components.Schemas[name]
+		if !ok {
+			t.Fatalf("unresolved reference %s", s.Ref)
+		}
+		return doc.properties(t, target)
+	}
+	for name := range s.Properties {
+		props[name] = true
+	}
+	for _, part := range s.AllOf {
+		for name := range doc.properties(t, part) {
+			props[name] = true
+		}
+	}
+	return props
+}
+
+func TestPathsMatchRoutes(t *testing.T) {
+	doc := load(t)
+	routes := handlers.NewEventHandler(database.NewMemoryStore(), handlers.Limits{}).Routes()
+
+	var fromRoutes, fromSpec []string
+	for _, route := range routes {
+		fromRoutes = append(fromRoutes, route.Method+" "+route.Pattern)
+	}
+	wildcard := regexp.MustCompile(`\{(\w+)\}`)
+	for path, item := range doc.Paths {
+		for key := range item {
+			if key == "parameters" {
+				continue
+			}
+			fromSpec = append(fromSpec, strings.ToUpper(key)+" "+path)
+		}
+
+		// Every wildcard is described as a path parameter
+		var declared []string
+		var params []parameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &params); err != nil {
+				t.Fatalf("%s: invalid parameters: %v", path, err)
+			}
+		}
+		for _, p := range params {
+			if p.Ref != "" {
+				p = doc.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			}
+			if p.In == "path" {
+				declared = append(declared, p.Name)
+			}
+		}
+		var wildcards []string
+		for _, m := range wildcard.FindAllStringSubmatch(path, -1) {
+			wildcards = append(wildcards, m[1])
+		}
+		slices.Sort(declared)
+		slices.Sort(wildcards)
+		if !slices.Equal(declared, wildcards) {
+			t.Errorf("%s declares path parameters %q, want %q", path, declared, wildcards)
+		}
+	}
+
+	slices.Sort(fromRoutes)
+	slices.Sort(fromSpec)
+	for _, op := range fromRoutes {
+		if !slices.Contains(fromSpec, op) {
+			t.Errorf("route %s is not in openapi.json", op)
+		}
+	}
+	for _, op := range fromSpec {
+		if !slices.Contains(fromRoutes, op) {
+			t.Errorf("openapi.json describes %s, which is not a route", op)
+		}
+	}
+}
+
+func TestSchemasMatchModels(t *testing.T) {
+	doc := load(t)
+
+	for name := range doc.Components.Schemas {
+		if _, ok := schemaTypes[name]; !ok && !slices.Contains(scalarSchemas, name) {
+			t.Errorf("schema %s has no Go type to compare with", name)
+		}
+	}
+
+	for name, value := range schemaTypes {
+		t.Run(name, func(t *testing.T) {
+			s, ok := doc.Components.Schemas[name]
+			if !ok {
+				t.Fatalf("openapi.json has no %s schema", name)
+			}
+			props := doc.properties(t, s)
+			fields := jsonFields(reflect.TypeOf(value))
+
+			for field := range fields {
+				if !props[field] {
+					t.Errorf("field %s is missing from the schema", field)
+				}
+			}
+			for prop := range props {
+				if _, ok := fields[prop]; !ok {
+					t.Errorf("property %s is not a field of %T", prop, value)
+				}
+			}
+			for _, req := range s.Required {
+				if !props[req] {
+					t.Errorf("required property %s is not defined", req)
+				}
+			}
+
+			if !slices.Contains(responseSchemas, name) {
+				return
+			}
+			for field, omitted := range fields {
+				if required := slices.Contains(s.Required, field); required == omitted {
+					t.Errorf("%s is required = %t, but omitempty = %t", field, required, omitted)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("status %d with Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !bytes.Equal(rec.Body.Bytes(), Spec) {
+		t.Error("served document differs from Spec")
+	}
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseProxies(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.1", "192.168.1.77/24", "::ffff:172.16.0.9", "2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.1/32", "192.168.1.0/24", "172.16.0.9/32", "2001:db8::/32"}
+	if len(proxies) != len(want) {
+		t.Fatalf("got %d proxies, want %d", len(proxies), len(want))
+	}
+	for i, prefix := range proxies {
+		if prefix.String() != want[i] {
+			t.Errorf("proxy %d = %s, want %s", i, prefix, want[i])
+		}
+	}
+
+	for _, bad := range []string{"10.0.0", "10.0.0.0/33", "proxy.example.com", ""} {
+		if _, err := ParseProxies([]string{bad}); err == nil {
+			t.Errorf("ParseProxies(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.0/8", "2001:db8::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"no proxy", "203.0.113.5:4711", nil, "203.0.113.5"},
+		{"untrusted peer with XFF", "203.0.113.5:4711", []string{"198.51.100.7"}, "203.0.113.5"},
+		{"untrusted peer spoofing a proxy", "203.0.113.5:4711", []string{"10.0.0.2"}, "203.0.113.5"},
+		{"trusted peer without XFF", "10.0.0.1:4711", nil, "10.0.0.1"},
+		{"trusted peer", "10.0.0.1:4711", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"multi-hop", "10.0.0.1:4711", []string{"198.51.100.7, 10.1.2.3, 10.0.0.9"}, "198.51.100.7"},
+		{"spoofed left of the client", "10.0.0.1:4711", []string{"1.2.3.4, 198.51.100.7, 10.0.0.9"}, "198.51.100.7"},
+		{"spoofed trusted entry left of the client", "10.0.0.1:4711", []string{"10.9.9.9, 198.51.100.7"}, "198.51.100.7"},
+		{"all hops trusted", "10.0.0.1:4711", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"garbage from the client", "10.0.0.1:4711", []string{"not-an-ip, 198.51.100.7"}, "198.51.100.7"},
+		{"garbage from the last proxy", "10.0.0.1:4711", []string{"198.51.100.7, unknown"}, "10.0.0.1"},
+		{"empty entries", "10.0.0.1:4711", []string{" , "}, "10.0.0.1"},
+		{"address with port", "10.0.0.1:4711", []string{"198.51.100.7:5555"}, "10.0.0.1"},
+		{"IPv4-mapped peer", "[::ffff:10.0.0.1]:4711", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"IPv4-mapped untrusted peer", "[::ffff:203.0.113.5]:4711", []string{"198.51.100.7"}, "203.0.113.5"},
+		{"IPv4-mapped client", "10.0.0.1:4711", []string{"::ffff:198.51.100.7"}, "198.51.100.7"},
+		{"IPv6 proxy", "[2001:db8::1]:4711", []string{"2001:db8:ffff::42"}, "2001:db8:ffff::42"},
+		{"several headers", "10.0.0.1:4711", []string{"1.2.3.4, 198.51.100.7", "10.0.0.5", "10.0.0.6"}, "198.51.100.7"},
+		{"several headers, client in the last", "10.0.0.1:4711", []string{"1.2.3.4", "198.51.100.7"}, "198.51.100.7"},
+		{"remote without port", "203.0.113.5", nil, "203.0.113.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := proxies.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPWithoutProxies(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:4711"
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+	if got := Proxies(nil).ClientIP(r); got != "10.0.0.1" {
+		t.Errorf("ClientIP = %s, want the peer when no proxy is trusted", got)
+	}
+}
@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2035, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestAllow(t *testing.T) {
+	l := New(2, 3) // 2 tokens a second, bursts of 3
+	now := epoch
+
+	for i := range 3 {
+		if ok, _ := l.Allow("a", now); !ok {
+			t.Fatalf("request %d of the burst was refused", i+1)
+		}
+	}
+	ok, wait := l.Allow("a", now)
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("Allow after the burst = %t, %v; want false, 500ms", ok, wait)
+	}
+
+	// Other keys have their own bucket
+	if ok, _ := l.Allow("b", now); !ok {
+		t.Error("a second key was refused")
+	}
+
+	// Part of a token is not enough, and the wait shrinks accordingly
+	now = now.Add(200 * time.Millisecond)
+	if ok, wait := l.Allow("a", now); ok || wait != 300*time.Millisecond {
+		t.Errorf("Allow after 200ms = %t, %v; want false, 300ms", ok, wait)
+	}
+
+	// Waiting as long as told is enough
+	now = now.Add(300 * time.Millisecond)
+	if ok, _ := l.Allow("a", now); !ok {
+		t.Error("refused after waiting for the Retry-After")
+	}
+	if ok, _ := l.Allow("a", now); ok {
+		t.Error("one refilled token was taken twice")
+	}
+
+	// Refilling stops at burst
+	now = now.Add(time.Hour)
+	for i := range 3 {
+		if ok, _ := l.Allow("a", now); !ok {
+			t.Fatalf("request %d after an hour was refused", i+1)
+		}
+	}
+	if ok, _ := l.Allow("a", now); ok {
+		t.Error("bucket refilled beyond burst")
+	}
+}
+
+func TestAllowWaitRoundsUp(t *testing.T) {
+	l := New(3, 1)
+	l.Allow("a", epoch)
+	_, wait := l.Allow("a", epoch)
+	if wait < time.Second/3 || wait > time.Second/3+time.Nanosecond {
+		t.Errorf("wait = %v, want 1/3s rounded up", wait)
+	}
+}
+
+func TestAllowClockGoingBackwards(t *testing.T) {
+	l := New(1, 1)
+	l.Allow("a", epoch)
+	if ok, _ := l.Allow("a", epoch.Add(-time.Hour)); ok {
+		t.Error("an earlier time refilled the bucket")
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	l := New(0, 10)
+	if l != nil {
+		t.Fatal("New(0, 10) is not nil")
+	}
+	for range 100 {
+		if ok, wait := l.Allow("a", epoch); !ok || wait != 0 {
+			t.Fatal("a nil limiter refused a request")
+		}
+	}
+	if New(-1, 10) != nil {
+		t.Error("New(-1, 10) is not nil")
+	}
+	if l := New(1, 0); l.burst != 1 {
+		t.Errorf("burst = %v, want at least 1", l.burst)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	l := New(1, 2)
+	l.Allow("idle", epoch)
+	l.Allow("busy", epoch)
+	l.Allow("busy", epoch)
+	if len(l.buckets) != 2 {
+		t.Fatalf("%d buckets, want 2", len(l.buckets))
+	}
+
+	// Before the interval nothing is swept, even full buckets
+	now := epoch.Add(sweepInterval - time.Second)
+	l.Allow("busy", now)
+	l.Allow("busy", now)
+	if len(l.buckets) != 2 {
+		t.Fatalf("swept early: %d buckets", len(l.buckets))
+	}
+
+	// idle has refilled; busy has not and keeps its state
+	now = epoch.Add(sweepInterval)
+	l.Allow("other", now)
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("a full bucket was kept")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("a bucket still in use was dropped")
+	}
+	if ok, _ := l.Allow("busy", now); !ok {
+		t.Error("busy did not keep its refilled token")
+	}
+	if ok, _ := l.Allow("busy", now); ok {
+		t.Error("sweeping reset a bucket that was not full")
+	}
+	if !l.lastSweep.Equal(now) {
+		t.Errorf("lastSweep = %v, want %v", l.lastSweep, now)
+	}
+}
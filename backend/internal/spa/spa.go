@@ -0,0 +1,211 @@
+// Package spa serves a single-page web app: its static files with suitable
+// cache headers, and index.html for every other path so client-side routes
+// such as /event/{id}/results can be opened directly.
+package spa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// indexFile is served for paths that are not files
+	indexFile = "index.html"
+
+	// assetsDir holds the bundles Vite names after their content hash, which
+	// can be cached forever
+	assetsDir = "assets/"
+
+	// Cache-Control values for hashed assets, other files and development
+	cacheImmutable  = "public, max-age=31536000, immutable"
+	cacheRevalidate = "no-cache"
+	cacheNone       = "no-store"
+)
+
+// extraTypes are content types missing from Go's built-in table on some systems
+var extraTypes = map[string]string{
+	".js":          "text/javascript; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".map":         "application/json",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".ico":         "image/x-icon",
+	".webmanifest": "application/manifest+json",
+}
+
+// file is a static file ready to be served
+type file struct {
+	data        []byte
+	etag        string
+	contentType string
+}
+
+// Handler serves an app from a file system
+type Handler struct {
+	fsys fs.FS
+
+	// files holds every file read up front, or nil to read them on each
+	// request
+	files map[string]*file
+}
+
+// New creates a handler that reads every file in fsys up front. Use it for
+// file systems that do not change, such as an embedded build. It fails if
+// fsys has no index.html.
+func New(fsys fs.FS) (*Handler, error) {
+	h := &Handler{fsys: fsys, files: make(map[string]*file)}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || hidden(name) {
+			return err
+		}
+		f, err := load(fsys, name)
+		if err != nil {
+			return err
+		}
+		h.files[name] = f
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app files: %w", err)
+	}
+
+	if _, ok := h.files[indexFile]; !ok {
+		return nil, fmt.Errorf("app has no %s", indexFile)
+	}
+	return h, nil
+}
+
+// NewDev creates a handler that reads files on every request and forbids
+// caching, so a rebuilt app shows up on the next reload
+func NewDev(fsys fs.FS) *Handler {
+	return &Handler{fsys: fsys}
+}
+
+// ServeHTTP serves the requested file, or index.html for client-side routes
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = indexFile
+	}
+
+	f, err := h.open(name)
+	switch {
+	case err == nil:
+	case errors.Is(err, fs.ErrNotExist) && path.Ext(name) == "":
+		// A client-side route; let the app render it
+		name = indexFile
+		f, err = h.open(name)
+		if err != nil {
+			fail(w, r, err)
+			return
+		}
+	default:
+		fail(w, r, err)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", f.contentType)
+	header.Set("ETag", f.etag)
+	switch {
+	case h.files == nil:
+		header.Set("Cache-Control", cacheNone)
+	case strings.HasPrefix(name, assetsDir):
+		header.Set("Cache-Control", cacheImmutable)
+	default:
+		header.Set("Cache-Control", cacheRevalidate)
+	}
+
+	// ServeContent answers If-None-Match and Range requests
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(f.data))
+}
+
+// open finds a file by its clean relative name
+func (h *Handler) open(name string) (*file, error) {
+	if hidden(name) {
+		return nil, fs.ErrNotExist
+	}
+	if h.files != nil {
+		f, ok := h.files[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return f, nil
+	}
+	return load(h.fsys, name)
+}
+
+// fail answers a request for a file that could not be served
+func fail(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		http.NotFound(w, r)
+		return
+	}
+
+	slog.ErrorContext(r.Context(), "failed to serve app file", "path", r.URL.Path, "error", err)
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
+
+// load reads a file and works out its headers
+func load(fsys fs.FS, name string) (*file, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fs.ErrNotExist
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return &file{
+		data:        data,
+		etag:        `"` + hex.EncodeToString(sum[:8]) + `"`,
+		contentType: contentType(name, data),
+	}, nil
+}
+
+// contentType picks the Content-Type for a file from its extension, falling
+// back to sniffing the content
+func contentType(name string, data []byte) string {
+	ext := strings.ToLower(path.Ext(name))
+	if t, ok := extraTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// hidden reports whether any part of a path starts with a dot, like the
+// placeholder kept in an unbuilt dist directory
+func hidden(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package spa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+const indexHTML = `<!doctype html><html><body><div id="root"></div></body></html>`
+
+// app is a small Vite build
+func app() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":                {Data: []byte(indexHTML)},
+		"favicon.ico":               {Data: []byte{0, 0, 1, 0}},
+		"assets/index-3f2a9c1e.js":  {Data: []byte("console.log('app')")},
+		"assets/index-7b4d4e8a.css": {Data: []byte("body{margin:0}")},
+		".gitkeep":                  {Data: nil},
+		".env":                      {Data: []byte("SECRET=1")},
+		"assets/.secret.js":         {Data: []byte("nope")},
+		".well-known/security.txt":  {Data: []byte("Contact: nobody")},
+	}
+}
+
+func newHandler(t *testing.T) *Handler {
+	t.Helper()
+	h, err := New(app())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func get(h http.Handler, method, path string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServe(t *testing.T) {
+	h := newHandler(t)
+
+	tests := []struct {
+		path        string
+		status      int
+		body        string // empty to skip
+		contentType string
+		cache       string
+	}{
+		{"/", http.StatusOK, indexHTML, "text/html; charset=utf-8", cacheRevalidate},
+		{"/index.html", http.StatusOK, indexHTML, "text/html; charset=utf-8", cacheRevalidate},
+		{"/event/3f2a9c1e/results", http.StatusOK, indexHTML, "text/html; charset=utf-8", cacheRevalidate},
+		{"/event/3f2a9c1e", http.StatusOK, indexHTML, "text/html; charset=utf-8", cacheRevalidate},
+		{"/assets", http.StatusOK, indexHTML, "text/html; charset=utf-8", cacheRevalidate},
+		{"/assets/index-3f2a9c1e.js", http.StatusOK, "console.log('app')", "text/javascript; charset=utf-8", cacheImmutable},
+		{"/assets/index-7b4d4e8a.css", http.StatusOK, "body{margin:0}", "text/css; charset=utf-8", cacheImmutable},
+		{"/favicon.ico", http.StatusOK, "", "image/x-icon", cacheRevalidate},
+		{"/assets/index-deadbeef.js", http.StatusNotFound, "", "", ""},
+		{"/robots.txt", http.StatusNotFound, "", "", ""},
+		{"/event/3f2a9c1e/event.ics", http.StatusNotFound, "", "", ""},
+		{"/.env", http.StatusNotFound, "", "", ""},
+		{"/.gitkeep", http.StatusNotFound, "", "", ""},
+		{"/assets/.secret.js", http.StatusNotFound, "", "", ""},
+		{"/.well-known/security.txt", http.StatusNotFound, "", "", ""},
+		// Paths are cleaned, so they cannot leave the app
+		{"/../../etc/passwd", http.StatusOK, indexHTML, "text/html; charset=utf-8", cacheRevalidate},
+		{"/../../etc/hosts.txt", http.StatusNotFound, "", "", ""},
+		{"/assets/../favicon.ico", http.StatusOK, "", "image/x-icon", cacheRevalidate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := get(h, http.MethodGet, tt.path)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body, tt.body)
+			}
+			if tt.contentType != "" && rec.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.contentType)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cache {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cache)
+			}
+			if tt.status == http.StatusOK && rec.Header().Get("ETag") == "" {
+				t.Error("no ETag")
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	h := newHandler(t)
+
+	first := get(h, http.MethodGet, "/")
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("index.html has no ETag")
+	}
+	if other := get(h, http.MethodGet, "/event/x/results").Header().Get("ETag"); other != etag {
+		t.Errorf("fallback ETag %s differs from index.html's %s", other, etag)
+	}
+	if js := get(h, http.MethodGet, "/assets/index-3f2a9c1e.js").Header().Get("ETag"); js == etag {
+		t.Error("different files share an ETag")
+	}
+
+	rec := get(h, http.MethodGet, "/", "If-None-Match", etag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("If-None-Match = %d with %d bytes, want 304 and no body", rec.Code, rec.Body.Len())
+	}
+	if got := get(h, http.MethodGet, "/", "If-None-Match", `"stale"`).Code; got != http.StatusOK {
+		t.Errorf("stale If-None-Match = %d, want 200", got)
+	}
+}
+
+func TestMethods(t *testing.T) {
+	h := newHandler(t)
+
+	rec := get(h, http.MethodHead, "/event/x/results")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("HEAD = %d with %d bytes", rec.Code, rec.Body.Len())
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		rec := get(h, method, "/")
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s = %d, want 405", method, rec.Code)
+		}
+		if got := rec.Header().Get("Allow"); got != "GET, HEAD" {
+			t.Errorf("%s Allow = %q", method, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	h := newHandler(t)
+	for name := range h.files {
+		if hidden(name) {
+			t.Errorf("hidden file %s was loaded", name)
+		}
+	}
+	if len(h.files) != 4 {
+		t.Errorf("loaded %d files, want 4", len(h.files))
+	}
+
+	// The placeholder of an unbuilt frontend is not an app
+	if _, err := New(fstest.MapFS{".gitkeep": {}}); err == nil {
+		t.Error("New accepted a directory without index.html")
+	}
+	if _, err := New(fstest.MapFS{}); err == nil {
+		t.Error("New accepted an empty directory")
+	}
+}
+
+func TestDev(t *testing.T) {
+	fsys := app()
+	h := NewDev(fsys)
+
+	rec := get(h, http.MethodGet, "/assets/index-3f2a9c1e.js")
+	if got := rec.Header().Get("Cache-Control"); got != cacheNone {
+		t.Errorf("Cache-Control = %q, want %q", got, cacheNone)
+	}
+	rec = get(h, http.MethodGet, "/event/x/results")
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != cacheNone {
+		t.Errorf("fallback = %d with Cache-Control %q", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+	if got := get(h, http.MethodGet, "/.env").Code; got != http.StatusNotFound {
+		t.Errorf("hidden file = %d, want 404", got)
+	}
+
+	// A rebuild shows up on the next request
+	fsys["index.html"] = &fstest.MapFile{Data: []byte("rebuilt")}
+	fsys["assets/index-new.js"] = &fstest.MapFile{Data: []byte("new")}
+	if got := get(h, http.MethodGet, "/").Body.String(); got != "rebuilt" {
+		t.Errorf("index.html = %q after a rebuild", got)
+	}
+	if got := get(h, http.MethodGet, "/assets/index-new.js").Code; got != http.StatusOK {
+		t.Errorf("new asset = %d, want 200", got)
+	}
+
+	// Without index.html client-side routes are not found
+	delete(fsys, "index.html")
+	if got := get(h, http.MethodGet, "/event/x/results").Code; got != http.StatusNotFound {
+		t.Errorf("fallback without index.html = %d, want 404", got)
+	}
+}
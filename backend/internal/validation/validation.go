@@ -0,0 +1,239 @@
+// Package validation checks request payloads before they reach the database.
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+// MaxNameLength is the longest event name accepted, in characters
+const MaxNameLength = 200
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+	Field   string `json:"field"` // e.g. "dates[1].start_time"
+	Message string `json:"message"`
+}
+
+// Errors collects every field problem found in a request
+type Errors []FieldError
+
+// Error implements the error interface
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// add records a problem with a field
+func (e *Errors) add(field, format string, args ...any) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// option is a parsed date option
+type option struct {
+	field      string
+	start, end time.Time
+}
+
+// CreateEvent validates a request to create an event. It returns nil if the
+// request is valid.
+func CreateEvent(req models.CreateEventRequest, now time.Time) Errors {
+	var errs Errors
+	checkName(&errs, req.Name)
+
+	tzName := req.TimeZone
+	if tzName == "" {
+		tzName = models.DefaultTimeZone
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		errs.add("time_zone", "is not a known IANA time zone")
+		// Still check the dates, just without a zone to place them in
+		loc = time.UTC
+	}
+
+	if len(req.Dates) == 0 {
+		errs.add("dates", "at least one date option is required")
+	}
+
+	var options []option
+	for i, dateReq := range req.Dates {
+		field := fmt.Sprintf("dates[%d]", i)
+		if opt, ok := parseOption(&errs, field, dateReq, loc, now); ok {
+			options = append(options, opt)
+		}
+	}
+	checkOverlaps(&errs, options, nil)
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// UpdateEvent validates a request to edit an event
+func UpdateEvent(req models.UpdateEventRequest) Errors {
+	var errs Errors
+	checkName(&errs, req.Name)
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// SubmitResponse validates a respondent's availability submission
+func SubmitResponse(req models.SubmitResponseRequest) Errors {
+	var errs Errors
+
+	if strings.TrimSpace(req.Name) == "" {
+		errs.add("name", "is required")
+	}
+	if len(req.Responses) == 0 {
+		errs.add("responses", "at least one response is required")
+	}
+	for i, response := range req.Responses {
+		if !response.ResolvedAvailability().Valid() {
+			errs.add(fmt.Sprintf("responses[%d].availability", i), "must be one of yes, maybe or no")
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkName validates an event name
+func checkName(errs *Errors, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		errs.add("name", "is required")
+	} else if len([]rune(name)) > MaxNameLength {
+		errs.add("name", "must be at most %d characters", MaxNameLength)
+	}
+}
+
+// AddDate validates a new date option for an existing event, including that
+// it does not overlap the event's current options
+func AddDate(req models.CreateDateRequest, event *models.Event, now time.Time) Errors {
+	var errs Errors
+
+	loc, err := event.Location()
+	if err != nil {
+		errs.add("time_zone", "event has an unknown time zone")
+		return errs
+	}
+
+	opt, ok := parseOption(&errs, "", req, loc, now)
+	if ok {
+		var existing []option
+		for _, date := range event.Dates {
+			start, end, err := date.Range(loc)
+			if err != nil {
+				continue
+			}
+			existing = append(existing, option{field: fmt.Sprintf("date option %d", date.ID), start: start, end: end})
+		}
+		checkOverlaps(&errs, []option{opt}, existing)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// parseOption validates a single date option. Field names are prefixed with
+// prefix, e.g. "dates[0]". It reports whether the option could be parsed.
+func parseOption(errs *Errors, prefix string, req models.CreateDateRequest, loc *time.Location, now time.Time) (option, bool) {
+	field := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+
+	day, dayErr := time.Parse("2006-01-02", req.Date)
+	if req.Date == "" {
+		errs.add(field("date"), "is required")
+	} else if dayErr != nil {
+		errs.add(field("date"), "must be a valid date in YYYY-MM-DD format")
+	}
+
+	startClock, startOK := parseClock(errs, field("start_time"), req.StartTime)
+	endClock, endOK := parseClock(errs, field("end_time"), req.EndTime)
+
+	if dayErr != nil || !startOK || !endOK {
+		return option{}, false
+	}
+
+	if !endClock.After(startClock) {
+		errs.add(field("end_time"), "must be after start_time")
+		return option{}, false
+	}
+
+	start, startExists := models.ResolveLocalTime(day.Year(), day.Month(), day.Day(), startClock.Hour(), startClock.Minute(), loc)
+	end, endExists := models.ResolveLocalTime(day.Year(), day.Month(), day.Day(), endClock.Hour(), endClock.Minute(), loc)
+
+	ok := true
+	if !startExists {
+		errs.add(field("start_time"), "does not exist on this date in %s because of a daylight saving change", loc)
+		ok = false
+	}
+	if !endExists {
+		errs.add(field("end_time"), "does not exist on this date in %s because of a daylight saving change", loc)
+		ok = false
+	}
+	if ok && start.Before(now) {
+		errs.add(field("date"), "must not be in the past")
+		ok = false
+	}
+
+	name := prefix
+	if name == "" {
+		name = "date option"
+	}
+	return option{field: name, start: start, end: end}, ok
+}
+
+// parseClock parses a HH:MM time of day
+func parseClock(errs *Errors, field, value string) (time.Time, bool) {
+	if value == "" {
+		errs.add(field, "is required")
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil || len(value) != len("15:04") {
+		errs.add(field, "must be a valid time in HH:MM format")
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// checkOverlaps reports options that duplicate or overlap an earlier option
+// in the same list or any option in existing
+func checkOverlaps(errs *Errors, options []option, existing []option) {
+	seen := append([]option{}, existing...)
+	for _, opt := range options {
+		for _, other := range seen {
+			if opt.start.Equal(other.start) && opt.end.Equal(other.end) {
+				errs.add(opt.field, "duplicates %s", other.field)
+				break
+			}
+			if opt.start.Before(other.end) && other.start.Before(opt.end) {
+				errs.add(opt.field, "overlaps %s", other.field)
+				break
+			}
+		}
+		seen = append(seen, opt)
+	}
+}
@@ -0,0 +1,249 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jleikdra/finn-en-dato/backend/internal/models"
+)
+
+var now = time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// fields lists the field paths of errs in order
+func fields(errs Errors) []string {
+	var out []string
+	for _, fe := range errs {
+		out = append(out, fe.Field)
+	}
+	return out
+}
+
+// slot builds a date option request
+func slot(date, start, end string) models.CreateDateRequest {
+	return models.CreateDateRequest{Date: date, StartTime: start, EndTime: end}
+}
+
+func TestCreateEvent(t *testing.T) {
+	valid := slot("2030-06-01", "18:00", "20:00")
+
+	tests := []struct {
+		name   string
+		req    models.CreateEventRequest
+		fields []string
+	}{
+		{
+			name: "valid",
+			req:  models.CreateEventRequest{Name: "Julebord", Dates: []models.CreateDateRequest{valid, slot("2030-06-02", "18:00", "20:00")}},
+		},
+		{
+			name:   "missing name and dates",
+			req:    models.CreateEventRequest{Name: "   "},
+			fields: []string{"name", "dates"},
+		},
+		{
+			name:   "name too long",
+			req:    models.CreateEventRequest{Name: strings.Repeat("ø", MaxNameLength+1), Dates: []models.CreateDateRequest{valid}},
+			fields: []string{"name"},
+		},
+		{
+			name:   "invalid date",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{valid, slot("2025-13-45", "18:00", "20:00")}},
+			fields: []string{"dates[1].date"},
+		},
+		{
+			name:   "invalid clock",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{valid, slot("2030-06-02", "25:99", "20:00")}},
+			fields: []string{"dates[1].start_time"},
+		},
+		{
+			name:   "single digit hour",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{valid, slot("2030-06-02", "9:00", "20:00")}},
+			fields: []string{"dates[1].start_time"},
+		},
+		{
+			name:   "missing fields",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{{}}},
+			fields: []string{"dates[0].date", "dates[0].start_time", "dates[0].end_time"},
+		},
+		{
+			name:   "end before start",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{slot("2030-06-02", "20:00", "18:00")}},
+			fields: []string{"dates[0].end_time"},
+		},
+		{
+			name:   "end equals start",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{slot("2030-06-02", "18:00", "18:00")}},
+			fields: []string{"dates[0].end_time"},
+		},
+		{
+			name:   "duplicate option",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{valid, slot("2030-06-02", "18:00", "20:00"), valid}},
+			fields: []string{"dates[2]"},
+		},
+		{
+			name:   "overlapping option",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{valid, slot("2030-06-01", "19:30", "21:00")}},
+			fields: []string{"dates[1]"},
+		},
+		{
+			name: "adjacent options",
+			req:  models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{valid, slot("2030-06-01", "20:00", "21:00")}},
+		},
+		{
+			name:   "in the past",
+			req:    models.CreateEventRequest{Name: "x", Dates: []models.CreateDateRequest{slot("2029-12-31", "18:00", "20:00")}},
+			fields: []string{"dates[0].date"},
+		},
+		{
+			name:   "unknown time zone",
+			req:    models.CreateEventRequest{Name: "x", TimeZone: "Europe/Atlantis", Dates: []models.CreateDateRequest{valid}},
+			fields: []string{"time_zone"},
+		},
+		{
+			name:   "skipped by DST",
+			req:    models.CreateEventRequest{Name: "x", TimeZone: "Europe/Oslo", Dates: []models.CreateDateRequest{valid, slot("2030-03-31", "02:30", "04:00")}},
+			fields: []string{"dates[1].start_time"},
+		},
+		{
+			name:   "several problems",
+			req:    models.CreateEventRequest{Name: "", TimeZone: "Nowhere", Dates: []models.CreateDateRequest{slot("2030-06-02", "18:00", "1800"), valid, valid}},
+			fields: []string{"name", "time_zone", "dates[0].end_time", "dates[2]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CreateEvent(tt.req, now)
+			if got := fields(errs); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("fields = %q, want %q (%v)", got, tt.fields, errs)
+			}
+			if tt.fields == nil && errs != nil {
+				t.Errorf("CreateEvent returned an empty non-nil Errors")
+			}
+		})
+	}
+}
+
+func TestCreateEventMessages(t *testing.T) {
+	req := models.CreateEventRequest{
+		Name: "x",
+		Dates: []models.CreateDateRequest{
+			slot("2030-06-01", "18:00", "20:00"),
+			slot("2030-06-01", "18:00", "20:00"),
+			slot("2030-06-01", "19:00", "21:00"),
+			slot("2025-13-45", "18:00", "20:00"),
+			slot("2030-06-03", "20:00", "18:00"),
+		},
+	}
+	// Overlaps are checked after every option is parsed
+	want := Errors{
+		{Field: "dates[3].date", Message: "must be a valid date in YYYY-MM-DD format"},
+		{Field: "dates[4].end_time", Message: "must be after start_time"},
+		{Field: "dates[1]", Message: "duplicates dates[0]"},
+		{Field: "dates[2]", Message: "overlaps dates[0]"},
+	}
+	errs := CreateEvent(req, now)
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("CreateEvent = %v, want %v", errs, want)
+	}
+	if msg := errs.Error(); !strings.Contains(msg, "dates[3].date: must be a valid date") {
+		t.Errorf("Error() = %q", msg)
+	}
+}
+
+func TestAddDate(t *testing.T) {
+	event := &models.Event{
+		TimeZone: "America/New_York",
+		Dates: []models.EventDate{
+			{ID: 7, Date: "2030-06-01", StartTime: "18:00", EndTime: "20:00"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		req    models.CreateDateRequest
+		errors Errors
+	}{
+		{name: "valid", req: slot("2030-06-01", "20:00", "22:00")},
+		{
+			name:   "duplicate",
+			req:    slot("2030-06-01", "18:00", "20:00"),
+			errors: Errors{{Field: "date option", Message: "duplicates date option 7"}},
+		},
+		{
+			name:   "overlap",
+			req:    slot("2030-06-01", "17:00", "18:30"),
+			errors: Errors{{Field: "date option", Message: "overlaps date option 7"}},
+		},
+		{
+			name:   "invalid clock",
+			req:    slot("2030-06-01", "9:00", "25:99"),
+			errors: Errors{{Field: "start_time", Message: "must be a valid time in HH:MM format"}, {Field: "end_time", Message: "must be a valid time in HH:MM format"}},
+		},
+		{
+			name:   "in the past",
+			req:    slot("2029-06-01", "18:00", "20:00"),
+			errors: Errors{{Field: "date", Message: "must not be in the past"}},
+		},
+		{
+			name:   "skipped by DST",
+			req:    slot("2030-03-10", "01:00", "02:30"),
+			errors: Errors{{Field: "end_time", Message: "does not exist on this date in America/New_York because of a daylight saving change"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddDate(tt.req, event, now); !reflect.DeepEqual(got, tt.errors) {
+				t.Errorf("AddDate = %v, want %v", got, tt.errors)
+			}
+		})
+	}
+
+	broken := &models.Event{TimeZone: "Europe/Atlantis"}
+	if got := fields(AddDate(slot("2030-06-01", "18:00", "20:00"), broken, now)); !reflect.DeepEqual(got, []string{"time_zone"}) {
+		t.Errorf("AddDate with an unknown event time zone = %q", got)
+	}
+}
+
+func TestSubmitResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    models.SubmitResponseRequest
+		fields []string
+	}{
+		{
+			name: "valid",
+			req:  models.SubmitResponseRequest{Name: "Kari", Responses: []models.ResponseRequest{{EventDateID: 1, Availability: models.AvailabilityMaybe}}},
+		},
+		{
+			name:   "empty",
+			req:    models.SubmitResponseRequest{Name: " "},
+			fields: []string{"name", "responses"},
+		},
+		{
+			name: "unknown availability",
+			req: models.SubmitResponseRequest{Name: "Kari", Responses: []models.ResponseRequest{
+				{EventDateID: 1, Availability: models.AvailabilityYes},
+				{EventDateID: 2, Availability: "perhaps"},
+			}},
+			fields: []string{"responses[1].availability"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(SubmitResponse(tt.req)); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("fields = %q, want %q", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestUpdateEvent(t *testing.T) {
+	if errs := UpdateEvent(models.UpdateEventRequest{Name: "Sommerfest"}); errs != nil {
+		t.Errorf("UpdateEvent = %v", errs)
+	}
+	if got := fields(UpdateEvent(models.UpdateEventRequest{})); !reflect.DeepEqual(got, []string{"name"}) {
+		t.Errorf("fields = %q", got)
+	}
+}
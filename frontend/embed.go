@@ -0,0 +1,21 @@
+// Package frontend embeds the built web app so the server binary can serve
+// it from any working directory. Run npm run build in this directory before
+// building the server; until then only a placeholder is embedded.
+package frontend
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Dist returns the contents of the dist directory Vite builds into
+func Dist() fs.FS {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
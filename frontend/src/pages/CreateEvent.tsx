@@ -80,7 +80,7 @@ const CreateEvent: React.FC = () => {
         endTime: dto.endTime
       }));
 
-      const response = await fetch('/api/events', {
+      const response = await fetch('/api/v1/events', {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -96,6 +96,8 @@ const CreateEvent: React.FC = () => {
       }
 
       const event = await response.json();
+      // The admin token is only returned once, keep it for organizer actions
+      localStorage.setItem(`adminToken:${event.id}`, event.admin_token);
       setCreatedEventId(event.id);
     } catch (error) {
       console.error('Error creating event:', error);
@@ -84,7 +84,7 @@ const CreateEventWithCalendar: React.FC = () => {
         end_time: dto.endTime
       }));
 
-      const response = await fetch('/api/events', {
+      const response = await fetch('/api/v1/events', {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -100,6 +100,8 @@ const CreateEventWithCalendar: React.FC = () => {
       }
 
       const event = await response.json();
+      // The admin token is only returned once, keep it for organizer actions
+      localStorage.setItem(`adminToken:${event.id}`, event.admin_token);
       setCreatedEventId(event.id);
     } catch (error) {
       console.error('Error creating event:', error);
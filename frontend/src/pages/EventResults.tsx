@@ -57,7 +57,7 @@ const EventResults: React.FC = () => {
 
     const fetchResults = async () => {
       try {
-        const response = await fetch(`/api/events/${eventId}/results`);
+        const response = await fetch(`/api/v1/events/${eventId}/results`);
         if (!response.ok) {
           throw new Error('Failed to fetch results');
         }
@@ -77,10 +77,11 @@ const EventResults: React.FC = () => {
     if (!eventId || !confirm('Er du sikker på at du vil låse denne datoen?')) return;
 
     try {
-      const response = await fetch(`/api/events/${eventId}/finalize`, {
+      const response = await fetch(`/api/v1/events/${eventId}/finalize`, {
         method: 'PATCH',
         headers: {
           'Content-Type': 'application/json',
+          'X-Admin-Token': localStorage.getItem(`adminToken:${eventId}`) ?? '',
         },
         body: JSON.stringify({ event_date_id: eventDateId }),
       });
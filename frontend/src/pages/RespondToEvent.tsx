@@ -33,7 +33,7 @@ const RespondToEvent: React.FC = () => {
 
     const fetchEvent = async () => {
       try {
-        const response = await fetch(`/api/events/${eventId}`);
+        const response = await fetch(`/api/v1/events/${eventId}`);
         if (!response.ok) {
           throw new Error('Event not found');
         }
@@ -71,10 +71,12 @@ const RespondToEvent: React.FC = () => {
 
     setIsSubmitting(true);
     try {
-      const response = await fetch(`/api/events/${eventId}/respond`, {
+      const editTokenKey = `editToken:${eventId}:${respondentName}`;
+      const response = await fetch(`/api/v1/events/${eventId}/respond`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
+          'X-Edit-Token': localStorage.getItem(editTokenKey) ?? '',
         },
         body: JSON.stringify({
           name: respondentName,
@@ -83,7 +85,18 @@ const RespondToEvent: React.FC = () => {
       });
 
       if (!response.ok) {
-        throw new Error('Failed to submit response');
+        const error = await response.json().catch(() => null);
+        if (error?.code === 'respondent_exists') {
+          alert('Noen har allerede svart med dette navnet. Velg et annet navn.');
+          return;
+        }
+        throw new Error(`Failed to submit response: ${error?.code ?? response.status}`);
+      }
+
+      // The edit token is only returned on the first submission
+      const result = await response.json();
+      if (result.edit_token) {
+        localStorage.setItem(editTokenKey, result.edit_token);
       }
 
       setSubmitted(true);
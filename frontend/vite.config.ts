@@ -1,9 +1,21 @@
-import { defineConfig } from 'vite'
+import { defineConfig, type Plugin } from 'vite'
 import react from '@vitejs/plugin-react'
 
+// keepDistPlaceholder writes dist/.gitkeep with the build, since emptying the
+// output directory removes it and the Go server embeds dist
+function keepDistPlaceholder(): Plugin {
+  return {
+    name: 'keep-dist-placeholder',
+    apply: 'build',
+    generateBundle() {
+      this.emitFile({ type: 'asset', fileName: '.gitkeep', source: '' })
+    },
+  }
+}
+
 // https://vite.dev/config/
 export default defineConfig({
-  plugins: [react()],
+  plugins: [react(), keepDistPlaceholder()],
   server: {
     port: 3000,
     proxy: {